@@ -0,0 +1,205 @@
+package analysis
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"math"
+)
+
+// ReactionTurns is the number of turns a player keeps its current velocity before running towards a point. It makes
+// the players running towards a region to dominate it before the players running away from it.
+const ReactionTurns = 1.0
+
+// ControlCell is a cell of the control map
+type ControlCell struct {
+	// Center is the center point of the cell
+	Center physics.Point
+	// Owner is the team that arrives first at the cell. It is empty when no player was considered
+	Owner arena.TeamPlace
+	// Player is the number of the player that arrives first at the cell
+	Player arena.PlayerNumber
+	// ArrivalTurns is the number of turns the first player needs to arrive at the cell
+	ArrivalTurns float64
+	// Margin is the number of turns the owner arrives before the first opponent. It is infinite when the opponent
+	// team has no players.
+	Margin float64
+}
+
+// ControlMap is a rasterized view of the field telling which team dominates each region (pitch control). Each cell
+// is owned by the team of the player that arrives first at it.
+type ControlMap struct {
+	rules    units.Rules
+	cellSize int
+	cols     int
+	rows     int
+	cells    []ControlCell
+}
+
+// NewControlMap rasterizes the field in square cells whose side is `resolution` times units.BaseUnit, and finds the
+// owner of each cell considering the players positions and velocities in the snapshot. The field size and the player
+// speed are the ones of the default rules.
+func NewControlMap(snapshot arena.Snapshot, resolution int) *ControlMap {
+	return NewControlMapWithRules(snapshot, resolution, units.DefaultRules())
+}
+
+// NewControlMapWithRules works as NewControlMap using the field size and the player speed of the rules
+func NewControlMapWithRules(snapshot arena.Snapshot, resolution int, rules units.Rules) *ControlMap {
+	if resolution <= 0 {
+		resolution = 1
+	}
+	cellSize := resolution * units.BaseUnit
+	m := &ControlMap{
+		rules:    rules,
+		cellSize: cellSize,
+		cols:     int(math.Ceil(float64(rules.FieldWidth) / float64(cellSize))),
+		rows:     int(math.Ceil(float64(rules.FieldHeight) / float64(cellSize))),
+	}
+	m.cells = make([]ControlCell, m.cols*m.rows)
+
+	players := snapshot.Players()
+	starts := make([]physics.Point, len(players))
+	for i, player := range players {
+		starts[i] = reactionPoint(player)
+	}
+	for row := 0; row < m.rows; row++ {
+		for col := 0; col < m.cols; col++ {
+			m.cells[row*m.cols+col] = evaluateCell(m.cellCenter(col, row), players, starts, rules.PlayerMaxSpeed)
+		}
+	}
+	return m
+}
+
+// ArrivalTurns estimates the number of turns the player needs to arrive at the point running at the max speed of the
+// default rules
+func ArrivalTurns(player arena.Player, target physics.Point) float64 {
+	return ArrivalTurnsWithRules(player, target, units.DefaultRules())
+}
+
+// ArrivalTurnsWithRules works as ArrivalTurns using the player max speed of the rules
+func ArrivalTurnsWithRules(player arena.Player, target physics.Point, rules units.Rules) float64 {
+	start := reactionPoint(player)
+	return arrivalFrom(start, player, target, rules.PlayerMaxSpeed)
+}
+
+// CellSize returns the side size of the cells in game units
+func (m *ControlMap) CellSize() int {
+	return m.cellSize
+}
+
+// Cols returns the number of cells in the X axis
+func (m *ControlMap) Cols() int {
+	return m.cols
+}
+
+// Rows returns the number of cells in the Y axis
+func (m *ControlMap) Rows() int {
+	return m.rows
+}
+
+// Cell returns the cell in the column and row. The cell (0, 0) is the one at the bottom left corner of the field
+func (m *ControlMap) Cell(col, row int) ControlCell {
+	return m.cells[row*m.cols+col]
+}
+
+// CellAt returns the cell that contains the point. The second value is false when the point is out of the field
+func (m *ControlMap) CellAt(p physics.Point) (ControlCell, bool) {
+	if p.PosX < 0 || p.PosY < 0 || p.PosX > m.rules.FieldWidth || p.PosY > m.rules.FieldHeight {
+		return ControlCell{}, false
+	}
+	col := int(math.Min(float64(p.PosX/m.cellSize), float64(m.cols-1)))
+	row := int(math.Min(float64(p.PosY/m.cellSize), float64(m.rows-1)))
+	return m.Cell(col, row), true
+}
+
+// TeamArea returns the area (in square game units) of the field dominated by the team
+func (m *ControlMap) TeamArea(place arena.TeamPlace) int {
+	area := 0
+	for row := 0; row < m.rows; row++ {
+		for col := 0; col < m.cols; col++ {
+			if m.cells[row*m.cols+col].Owner == place {
+				area += m.cellArea(col, row)
+			}
+		}
+	}
+	return area
+}
+
+// TeamShare returns the fraction of the field dominated by the team (0 to 1)
+func (m *ControlMap) TeamShare(place arena.TeamPlace) float64 {
+	return float64(m.TeamArea(place)) / float64(m.rules.FieldWidth*m.rules.FieldHeight)
+}
+
+// BestFreeSpot finds the center of the cell within the radius from the point that is dominated by the team with the
+// largest margin over the opponents. When two cells have the same margin, the closest to the point is chosen.
+// The second value is false when the team does not dominate any cell in that area.
+func (m *ControlMap) BestFreeSpot(place arena.TeamPlace, near physics.Point, radius float64) (physics.Point, bool) {
+	var best *ControlCell
+	bestDistance := 0.0
+	for i := range m.cells {
+		cell := &m.cells[i]
+		if cell.Owner != place {
+			continue
+		}
+		distance := near.DistanceTo(cell.Center)
+		if distance > radius {
+			continue
+		}
+		if best == nil || cell.Margin > best.Margin || (cell.Margin == best.Margin && distance < bestDistance) {
+			best = cell
+			bestDistance = distance
+		}
+	}
+	if best == nil {
+		return physics.Point{}, false
+	}
+	return best.Center, true
+}
+
+func (m *ControlMap) cellCenter(col, row int) physics.Point {
+	minX, minY := col*m.cellSize, row*m.cellSize
+	maxX := int(math.Min(float64(minX+m.cellSize), float64(m.rules.FieldWidth)))
+	maxY := int(math.Min(float64(minY+m.cellSize), float64(m.rules.FieldHeight)))
+	return physics.Point{PosX: (minX + maxX) / 2, PosY: (minY + maxY) / 2}
+}
+
+// cellArea considers that the last column and row may be smaller than the others
+func (m *ControlMap) cellArea(col, row int) int {
+	width := int(math.Min(float64(m.cellSize), float64(m.rules.FieldWidth-col*m.cellSize)))
+	height := int(math.Min(float64(m.cellSize), float64(m.rules.FieldHeight-row*m.cellSize)))
+	return width * height
+}
+
+func evaluateCell(center physics.Point, players []arena.Player, starts []physics.Point, maxSpeed float64) ControlCell {
+	cell := ControlCell{Center: center, ArrivalTurns: math.Inf(1), Margin: math.Inf(1)}
+	bestOpponent := map[arena.TeamPlace]float64{arena.HomeTeam: math.Inf(1), arena.AwayTeam: math.Inf(1)}
+	for i, player := range players {
+		turns := arrivalFrom(starts[i], player, center, maxSpeed)
+		if turns < bestOpponent[player.TeamPlace] {
+			bestOpponent[player.TeamPlace] = turns
+		}
+		if turns < cell.ArrivalTurns {
+			cell.ArrivalTurns = turns
+			cell.Owner = player.TeamPlace
+			cell.Player = player.Number
+		}
+	}
+	if cell.Owner != "" {
+		cell.Margin = bestOpponent[cell.Owner.Opponent()] - cell.ArrivalTurns
+	}
+	return cell
+}
+
+// reactionPoint is where the player will be after keeping its velocity during the reaction time
+func reactionPoint(player arena.Player) physics.Point {
+	return physics.PredictPosition(player.Element, ReactionTurns)
+}
+
+func arrivalFrom(start physics.Point, player arena.Player, target physics.Point, maxSpeed float64) float64 {
+	reach := math.Max(0, start.DistanceTo(target)-float64(player.Size)/2)
+	if player.Velocity.Direction == nil || player.Velocity.Speed == 0 {
+		// a still player does not need to react
+		return reach / maxSpeed
+	}
+	return ReactionTurns + reach/maxSpeed
+}
@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewControlMap(t *testing.T) {
+	snapshot := arena.Snapshot{
+		HomeTeam: arena.Team{Place: arena.HomeTeam, Players: []arena.Player{{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 5000, PosY: 5000}}, Number: "1", TeamPlace: arena.HomeTeam}}},
+		AwayTeam: arena.Team{Place: arena.AwayTeam, Players: []arena.Player{{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 15000, PosY: 5000}}, Number: "1", TeamPlace: arena.AwayTeam}}},
+	}
+	control := NewControlMap(snapshot, 10)
+	assert.Equal(t, 20, control.Cols())
+	assert.Equal(t, 10, control.Rows())
+	assert.Equal(t, 10*units.BaseUnit, control.CellSize())
+
+	cell := control.Cell(0, 0)
+	assert.Equal(t, physics.Point{PosX: 500, PosY: 500}, cell.Center)
+	assert.Equal(t, arena.HomeTeam, cell.Owner)
+	assert.Equal(t, arena.PlayerNumber("1"), cell.Player)
+
+	cell, ok := control.CellAt(physics.Point{PosX: units.FieldWidth, PosY: units.FieldHeight})
+	assert.True(t, ok)
+	assert.Equal(t, arena.AwayTeam, cell.Owner)
+	_, ok = control.CellAt(physics.Point{PosX: -1})
+	assert.False(t, ok)
+
+	// symmetrical positions split the field
+	assert.Equal(t, units.FieldWidth*units.FieldHeight/2, control.TeamArea(arena.HomeTeam))
+	assert.Equal(t, 0.5, control.TeamShare(arena.AwayTeam))
+}
+
+func TestNewControlMap_Velocity(t *testing.T) {
+	home := arena.Player{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 9000, PosY: 5000}}, Number: "2", TeamPlace: arena.HomeTeam}
+	home.Velocity = physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, units.PlayerMaxSpeed)
+	away := arena.Player{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 11000, PosY: 5000}}, Number: "2", TeamPlace: arena.AwayTeam}
+	away.Velocity = physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, units.PlayerMaxSpeed)
+	snapshot := arena.Snapshot{
+		HomeTeam: arena.Team{Place: arena.HomeTeam, Players: []arena.Player{home}},
+		AwayTeam: arena.Team{Place: arena.AwayTeam, Players: []arena.Player{away}},
+	}
+	control := NewControlMap(snapshot, 1)
+	// the home player runs towards the center while the away player runs away from it
+	cell, _ := control.CellAt(physics.Point{PosX: 10050, PosY: 5000})
+	assert.Equal(t, arena.HomeTeam, cell.Owner)
+	assert.True(t, control.TeamArea(arena.HomeTeam) > control.TeamArea(arena.AwayTeam))
+}
+
+func TestControlMap_BestFreeSpot(t *testing.T) {
+	snapshot := arena.Snapshot{
+		HomeTeam: arena.Team{Place: arena.HomeTeam, Players: []arena.Player{{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 10000, PosY: 5000}}, Number: "5", TeamPlace: arena.HomeTeam}}},
+		AwayTeam: arena.Team{Place: arena.AwayTeam, Players: []arena.Player{
+			{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 12000, PosY: 5000}}, Number: "2", TeamPlace: arena.AwayTeam},
+			{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 10000, PosY: 7000}}, Number: "3", TeamPlace: arena.AwayTeam},
+		}},
+	}
+	control := NewControlMap(snapshot, 2)
+
+	spot, ok := control.BestFreeSpot(arena.HomeTeam, physics.Point{PosX: 11000, PosY: 5500}, 1000)
+	assert.True(t, ok)
+	cell, _ := control.CellAt(spot)
+	assert.Equal(t, arena.HomeTeam, cell.Owner)
+	assert.True(t, spot.PosX < 11000 && spot.PosY < 5500, "the best spot should be away from the opponents: %v", spot)
+
+	_, ok = control.BestFreeSpot(arena.HomeTeam, physics.Point{PosX: 19000, PosY: 9000}, 500)
+	assert.False(t, ok)
+}
+
+func TestArrivalTurns(t *testing.T) {
+	player := arena.Player{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 1000, PosY: 1000}}, Number: "4", TeamPlace: arena.HomeTeam}
+	assert.Equal(t, 8.0, ArrivalTurns(player, physics.Point{PosX: 2000, PosY: 1000}))
+
+	player.Velocity = physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: -1}, units.PlayerMaxSpeed)
+	assert.Equal(t, 10.0, ArrivalTurns(player, physics.Point{PosX: 2000, PosY: 1000}))
+}
+
+func TestNewControlMapWithRules(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.FieldWidth = 10000
+	rules.FieldHeight = 6000
+	rules.PlayerMaxSpeed = 200
+	snapshot := arena.Snapshot{
+		HomeTeam: arena.Team{Place: arena.HomeTeam, Players: []arena.Player{{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 2500, PosY: 3000}}, Number: "1", TeamPlace: arena.HomeTeam}}},
+		AwayTeam: arena.Team{Place: arena.AwayTeam, Players: []arena.Player{{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 7500, PosY: 3000}}, Number: "1", TeamPlace: arena.AwayTeam}}},
+	}
+	control := NewControlMapWithRules(snapshot, 10, rules)
+	assert.Equal(t, 10, control.Cols())
+	assert.Equal(t, 6, control.Rows())
+	assert.Equal(t, 0.5, control.TeamShare(arena.HomeTeam))
+	_, ok := control.CellAt(physics.Point{PosX: 12000, PosY: 3000})
+	assert.False(t, ok)
+
+	player := snapshot.HomeTeam.Players[0]
+	assert.Equal(t, 4.0, ArrivalTurnsWithRules(player, physics.Point{PosX: 3500, PosY: 3000}, rules))
+}
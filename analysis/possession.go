@@ -0,0 +1,351 @@
+package analysis
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"math"
+)
+
+// VelocityTolerance is the difference, in speed units, between the predicted and the announced ball velocities above
+// which the ball is considered touched by a player. It absorbs the rounding differences between the prediction and
+// the server.
+const VelocityTolerance = 1.0
+
+// EventType identifies the events found by the possession tracker
+type EventType string
+
+const (
+	// GoalEvent is a score change. The player is the last one who touched the ball, if it is from the scoring team
+	GoalEvent EventType = "goal"
+	// TouchEvent is a player catching or deflecting the ball
+	TouchEvent EventType = "touch"
+	// PossessionEvent is a team getting the ball. The player is the one who got it, and the other player is the last
+	// opponent who touched it
+	PossessionEvent EventType = "possession"
+	// PassEvent is a player getting the ball released by a teammate. The player is the passer and the other player is
+	// the receiver
+	PassEvent EventType = "pass"
+	// FailedPassEvent is an opponent getting the ball released by a player. The player is the passer and the other
+	// player is the opponent
+	FailedPassEvent EventType = "failed-pass"
+	// TackleEvent is a player taking the ball from an opponent holding it. The other player is the opponent
+	TackleEvent EventType = "tackle"
+	// ShotEvent is a ball released towards the opponent goal, crossing the goal line between the poles if nobody
+	// touches it
+	ShotEvent EventType = "shot"
+)
+
+// Event is something relevant that happened during the match
+type Event struct {
+	Turn   int                `json:"turn"`
+	State  arena.GameState    `json:"state"`
+	Type   EventType          `json:"type"`
+	Team   arena.TeamPlace    `json:"team"`
+	Player arena.PlayerNumber `json:"player,omitempty"`
+	// Other is the other player involved in the event (e.g. the pass receiver)
+	Other arena.PlayerNumber `json:"other,omitempty"`
+}
+
+// PlayerID identifies a player in the match
+type PlayerID struct {
+	Place  arena.TeamPlace
+	Number arena.PlayerNumber
+}
+
+// String formats the id as "place/number"
+func (id PlayerID) String() string {
+	return string(id.Place) + "/" + string(id.Number)
+}
+
+// Stats are the counters of a team or a player
+type Stats struct {
+	// PossessionTurns is the number of turns the team had the ball, or the number of turns the player held it
+	PossessionTurns int `json:"possession_turns"`
+	// Touches is the number of catches and deflections
+	Touches int `json:"touches"`
+	// Passes is the number of completed passes
+	Passes int `json:"passes"`
+	// FailedPasses is the number of passes that ended with an opponent
+	FailedPasses int `json:"failed_passes"`
+	// Tackles is the number of times the ball was taken from an opponent holding it
+	Tackles int `json:"tackles"`
+	// Shots is the number of shots
+	Shots int `json:"shots"`
+	// Goals is the number of goals
+	Goals int `json:"goals"`
+}
+
+// release is the last time a player released the ball
+type release struct {
+	player PlayerID
+	shot   bool
+}
+
+// PossessionTracker infers who has the ball from successive snapshots. Catches and releases are taken from the ball
+// holder, while the touches on a free ball are found when the ball velocity does not change as predicted (see
+// physics.Engine.ReflectOnBorders) and a player collides with it.
+type PossessionTracker struct {
+	engine    physics.Engine
+	field     arena.Field
+	previous  *arena.Snapshot
+	lastTouch *PlayerID
+	released  *release
+	owner     arena.TeamPlace
+	events    []Event
+	teams     map[arena.TeamPlace]*Stats
+	players   map[PlayerID]*Stats
+}
+
+// NewPossessionTracker creates a tracker without any snapshot that predicts the ball with the default rules
+func NewPossessionTracker() *PossessionTracker {
+	return NewPossessionTrackerWithRules(units.DefaultRules())
+}
+
+// NewPossessionTrackerWithRules creates a tracker without any snapshot that predicts the ball with the rules of the
+// match (e.g. the rules in a replay header)
+func NewPossessionTrackerWithRules(rules units.Rules) *PossessionTracker {
+	return &PossessionTracker{
+		engine:  physics.NewEngine(rules),
+		field:   arena.NewFieldFromRules(rules),
+		teams:   map[arena.TeamPlace]*Stats{arena.HomeTeam: {}, arena.AwayTeam: {}},
+		players: map[PlayerID]*Stats{},
+	}
+}
+
+// Update feeds the tracker with the next snapshot, and returns the events found in it. The same turn may be
+// announced more than once (e.g. in the listening and the playing states), and its repetitions are ignored unless the
+// score changes.
+func (t *PossessionTracker) Update(snapshot arena.Snapshot) []Event {
+	previous := t.previous
+	scored := previous != nil && (snapshot.HomeTeam.Score != previous.HomeTeam.Score || snapshot.AwayTeam.Score != previous.AwayTeam.Score)
+	if previous != nil && previous.Turn == snapshot.Turn && !scored {
+		return nil
+	}
+	t.previous = &snapshot
+	first := len(t.events)
+
+	if scored {
+		scorer := arena.HomeTeam
+		if snapshot.AwayTeam.Score != previous.AwayTeam.Score {
+			scorer = arena.AwayTeam
+		}
+		var player arena.PlayerNumber
+		if t.lastTouch != nil && t.lastTouch.Place == scorer {
+			player = t.lastTouch.Number
+			t.player(*t.lastTouch).Goals++
+		}
+		t.teams[scorer].Goals++
+		t.event(snapshot, GoalEvent, scorer, player, "")
+		// the kickoff starts a new play
+		t.lastTouch, t.released, t.owner = nil, nil, ""
+		return t.events[first:]
+	}
+	if snapshot.State == arena.Results || snapshot.State == arena.Over {
+		return t.events[first:]
+	}
+
+	holder := snapshot.Ball.Holder
+	var previousHolder *arena.Player
+	if previous != nil {
+		previousHolder = previous.Ball.Holder
+	}
+
+	switch {
+	case holder != nil && !samePlayer(holder, previousHolder):
+		t.touch(snapshot, PlayerID{Place: holder.TeamPlace, Number: holder.Number}, previousHolder)
+	case holder == nil && previousHolder != nil:
+		// the holder kicked the ball (or it was auto kicked)
+		id := PlayerID{Place: previousHolder.TeamPlace, Number: previousHolder.Number}
+		t.released = &release{player: id, shot: t.isShot(snapshot.Ball.Element, id.Place)}
+		if t.released.shot {
+			t.teams[id.Place].Shots++
+			t.player(id).Shots++
+			t.event(snapshot, ShotEvent, id.Place, id.Number, "")
+		}
+	case holder == nil && previous != nil && previous.State != arena.Results:
+		// the ball is moved to the field center after a goal, so it is not compared with its previous position
+		if id, ok := t.deflectedBy(*previous, snapshot); ok && (t.lastTouch == nil || *t.lastTouch != id) {
+			t.touch(snapshot, id, nil)
+		}
+	}
+
+	if holder != nil {
+		t.player(PlayerID{Place: holder.TeamPlace, Number: holder.Number}).PossessionTurns++
+	}
+	if t.owner != "" {
+		t.teams[t.owner].PossessionTurns++
+	}
+	return t.events[first:]
+}
+
+// touch registers a player touching the ball, finding the passes, tackles and possession changes. The previous
+// holder is set when the ball was taken from a player holding it.
+func (t *PossessionTracker) touch(snapshot arena.Snapshot, id PlayerID, previousHolder *arena.Player) {
+	t.teams[id.Place].Touches++
+	t.player(id).Touches++
+	t.event(snapshot, TouchEvent, id.Place, id.Number, "")
+
+	last, released := t.lastTouch, t.released
+	t.lastTouch, t.released = &id, nil
+	if last == nil || *last == id {
+		if t.owner != id.Place {
+			t.owner = id.Place
+			t.event(snapshot, PossessionEvent, id.Place, id.Number, "")
+		}
+		return
+	}
+
+	if last.Place == id.Place {
+		if released != nil && released.player == *last {
+			t.teams[id.Place].Passes++
+			t.player(*last).Passes++
+			t.event(snapshot, PassEvent, id.Place, last.Number, id.Number)
+		}
+		t.owner = id.Place
+		return
+	}
+
+	if previousHolder != nil && previousHolder.TeamPlace == last.Place && previousHolder.Number == last.Number {
+		t.teams[id.Place].Tackles++
+		t.player(id).Tackles++
+		t.event(snapshot, TackleEvent, id.Place, id.Number, last.Number)
+	} else if released != nil && released.player == *last && !released.shot {
+		t.teams[last.Place].FailedPasses++
+		t.player(*last).FailedPasses++
+		t.event(snapshot, FailedPassEvent, last.Place, last.Number, id.Number)
+	}
+	t.owner = id.Place
+	t.event(snapshot, PossessionEvent, id.Place, id.Number, last.Number)
+}
+
+// Events returns all events found until now
+func (t *PossessionTracker) Events() []Event {
+	return t.events
+}
+
+// Possession returns the team that has the ball, that is the team of the last player who touched it. It is empty
+// when nobody touched the ball since the kickoff.
+func (t *PossessionTracker) Possession() arena.TeamPlace {
+	return t.owner
+}
+
+// LastTouch returns the last player who touched the ball. The second value is false when nobody touched the ball
+// since the kickoff.
+func (t *PossessionTracker) LastTouch() (PlayerID, bool) {
+	if t.lastTouch == nil {
+		return PlayerID{}, false
+	}
+	return *t.lastTouch, true
+}
+
+// Team returns the stats of the team
+func (t *PossessionTracker) Team(place arena.TeamPlace) Stats {
+	if stats, ok := t.teams[place]; ok {
+		return *stats
+	}
+	return Stats{}
+}
+
+// Player returns the stats of the player
+func (t *PossessionTracker) Player(place arena.TeamPlace, number arena.PlayerNumber) Stats {
+	if stats, ok := t.players[PlayerID{Place: place, Number: number}]; ok {
+		return *stats
+	}
+	return Stats{}
+}
+
+// Players returns the stats of all players who did something
+func (t *PossessionTracker) Players() map[PlayerID]Stats {
+	players := make(map[PlayerID]Stats, len(t.players))
+	for id, stats := range t.players {
+		players[id] = *stats
+	}
+	return players
+}
+
+func (t *PossessionTracker) player(id PlayerID) *Stats {
+	stats, ok := t.players[id]
+	if !ok {
+		stats = &Stats{}
+		t.players[id] = stats
+	}
+	return stats
+}
+
+func (t *PossessionTracker) event(snapshot arena.Snapshot, eventType EventType, team arena.TeamPlace, player, other arena.PlayerNumber) {
+	t.events = append(t.events, Event{
+		Turn:   snapshot.Turn,
+		State:  snapshot.State,
+		Type:   eventType,
+		Team:   team,
+		Player: player,
+		Other:  other,
+	})
+}
+
+// deflectedBy finds the player who touched the free ball, when the ball velocity is not the one it should have after
+// the previous snapshot. The player must collide with the ball in its previous or current position, since the ball
+// may have left the player during the turn. The second value is false when the ball kept its velocity or when no
+// player collides with it.
+func (t *PossessionTracker) deflectedBy(previous, current arena.Snapshot) (PlayerID, bool) {
+	ball := previous.Ball.Element
+	_, expected, _ := t.engine.ReflectOnBorders(ball.Coords, ball.Velocity, ball.Size)
+	expected.Speed -= t.engine.Rules.BallDeceleration
+	if expected.Speed <= t.engine.Rules.BallMinSpeed {
+		expected.Speed = 0
+	}
+	if velocityDifference(expected, current.Ball.Velocity) <= VelocityTolerance {
+		return PlayerID{}, false
+	}
+	var closest *arena.Player
+	closestDistance := 0.0
+	players := current.Players()
+	for i := range players {
+		player := &players[i]
+		for _, position := range []physics.Element{current.Ball.Element, ball} {
+			collided, distance := player.HasCollided(&position)
+			if collided && (closest == nil || distance < closestDistance) {
+				closest, closestDistance = player, distance
+			}
+		}
+	}
+	if closest == nil {
+		return PlayerID{}, false
+	}
+	return PlayerID{Place: closest.TeamPlace, Number: closest.Number}, true
+}
+
+// velocityDifference returns the length of the difference between the velocities
+func velocityDifference(a, b physics.Velocity) float64 {
+	ax, ay := velocityComponents(a)
+	bx, by := velocityComponents(b)
+	return math.Hypot(ax-bx, ay-by)
+}
+
+func velocityComponents(v physics.Velocity) (float64, float64) {
+	if v.Direction == nil {
+		return 0, 0
+	}
+	return v.Speed * v.Direction.Cos(), v.Speed * v.Direction.Sin()
+}
+
+// isShot checks if the released ball is going to cross the opponent goal line between the poles
+func (t *PossessionTracker) isShot(ball physics.Element, team arena.TeamPlace) bool {
+	goal := t.field.Goal(team.Opponent())
+	from := ball.Coords
+	for _, to := range t.engine.BallTrajectory(ball.Coords, ball.Velocity, 0) {
+		if crossing, ok := arena.CheckGoalCrossing(from, to, t.engine.Rules.BallSize, goal); ok {
+			return crossing.Scored
+		}
+		from = to
+	}
+	return false
+}
+
+func samePlayer(a, b *arena.Player) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.TeamPlace == b.TeamPlace && a.Number == b.Number
+}
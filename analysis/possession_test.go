@@ -0,0 +1,207 @@
+package analysis
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/sim"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// newTestMatch creates a match where the home player 9 holds the ball. The away lineup is in the away team frame.
+func newTestMatch(home, away sim.Lineup) *sim.Simulator {
+	s := sim.New(sim.Config{HomeLineup: home, AwayLineup: away})
+	snapshot := s.Snapshot()
+	holder := snapshot.Player(arena.HomeTeam, "9").Copy()
+	snapshot.Ball.Coords = holder.Coords
+	snapshot.Ball.Holder = &holder
+	return sim.NewFromSnapshot(snapshot, 1, 0)
+}
+
+// play steps the match until the condition is true, feeding the tracker with every snapshot. The batches are sent in
+// the first turn, and the catch orders are sent by the catchers in all turns.
+func play(t *testing.T, s *sim.Simulator, tracker *PossessionTracker, until func(arena.Snapshot) bool, batches []orders.Batch, catchers ...PlayerID) {
+	for turn := 0; turn < 50; turn++ {
+		for _, catcher := range catchers {
+			batches = append(batches, orders.Batch{Place: catcher.Place, Number: catcher.Number, Orders: []orders.Order{orders.NewCatchOrder()}})
+		}
+		s.Step(batches)
+		batches = nil
+		snapshot := s.Snapshot()
+		tracker.Update(snapshot)
+		if until(snapshot) {
+			return
+		}
+	}
+	t.Fatal("the condition was never met")
+}
+
+func heldBy(place arena.TeamPlace, number arena.PlayerNumber) func(arena.Snapshot) bool {
+	return func(snapshot arena.Snapshot) bool {
+		holder := snapshot.Ball.Holder
+		return holder != nil && holder.TeamPlace == place && holder.Number == number
+	}
+}
+
+func kick(place arena.TeamPlace, number arena.PlayerNumber, velocity physics.Velocity) []orders.Batch {
+	return []orders.Batch{{Place: place, Number: number, Orders: []orders.Order{orders.NewKickOrder(velocity)}}}
+}
+
+func eventTypes(events []Event) []EventType {
+	var types []EventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestPossessionTracker_Passes(t *testing.T) {
+	// the away player 4 is close to the home player 10, in the away team frame
+	s := newTestMatch(
+		sim.Lineup{"9": {PosX: 10000, PosY: 5000}, "10": {PosX: 12000, PosY: 5000}},
+		sim.Lineup{"4": arena.Mirror(arena.AwayTeam).Point(physics.Point{PosX: 12000, PosY: 5250})},
+	)
+	tracker := NewPossessionTracker()
+	events := tracker.Update(s.Snapshot())
+	assert.Equal(t, []EventType{TouchEvent, PossessionEvent}, eventTypes(events))
+	assert.Equal(t, arena.HomeTeam, tracker.Possession())
+
+	// a pass from 9 to 10
+	play(t, s, tracker, heldBy(arena.HomeTeam, "10"), kick(arena.HomeTeam, "9", physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, 200)), PlayerID{arena.HomeTeam, "10"})
+	// 4 takes the ball from 10
+	play(t, s, tracker, heldBy(arena.AwayTeam, "4"), nil, PlayerID{arena.AwayTeam, "4"})
+	// 4 passes to 9, that is an opponent
+	play(t, s, tracker, heldBy(arena.HomeTeam, "9"), kick(arena.AwayTeam, "4", physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: -2000, PosY: -250}, 250)), PlayerID{arena.HomeTeam, "9"})
+
+	assert.Equal(t, []EventType{
+		TouchEvent, PossessionEvent,
+		TouchEvent, PassEvent,
+		TouchEvent, TackleEvent, PossessionEvent,
+		TouchEvent, FailedPassEvent, PossessionEvent,
+	}, eventTypes(tracker.Events()))
+	pass := tracker.Events()[3]
+	assert.Equal(t, Event{Turn: pass.Turn, State: arena.Listening, Type: PassEvent, Team: arena.HomeTeam, Player: "9", Other: "10"}, pass)
+	tackle := tracker.Events()[5]
+	assert.Equal(t, arena.AwayTeam, tackle.Team)
+	assert.Equal(t, arena.PlayerNumber("4"), tackle.Player)
+	assert.Equal(t, arena.PlayerNumber("10"), tackle.Other)
+
+	last, ok := tracker.LastTouch()
+	assert.True(t, ok)
+	assert.Equal(t, PlayerID{Place: arena.HomeTeam, Number: "9"}, last)
+	assert.Equal(t, arena.HomeTeam, tracker.Possession())
+
+	home := tracker.Team(arena.HomeTeam)
+	assert.Equal(t, 3, home.Touches)
+	assert.Equal(t, 1, home.Passes)
+	away := tracker.Team(arena.AwayTeam)
+	assert.Equal(t, 1, away.Tackles)
+	assert.Equal(t, 1, away.FailedPasses)
+	assert.True(t, home.PossessionTurns > 0 && away.PossessionTurns > 0)
+
+	assert.Equal(t, 1, tracker.Player(arena.HomeTeam, "9").Passes)
+	assert.Equal(t, 1, tracker.Player(arena.HomeTeam, "10").Touches)
+	assert.Equal(t, 1, tracker.Player(arena.AwayTeam, "4").FailedPasses)
+	assert.Equal(t, Stats{}, tracker.Player(arena.AwayTeam, "7"))
+	assert.Len(t, tracker.Players(), 3)
+	assert.Equal(t, "away/4", PlayerID{Place: arena.AwayTeam, Number: "4"}.String())
+}
+
+func TestPossessionTracker_ShotAndGoal(t *testing.T) {
+	s := newTestMatch(sim.Lineup{"9": {PosX: 17000, PosY: 5000}}, sim.Lineup{})
+	tracker := NewPossessionTracker()
+	tracker.Update(s.Snapshot())
+
+	scored := func(snapshot arena.Snapshot) bool { return snapshot.HomeTeam.Score > 0 }
+	play(t, s, tracker, scored, kick(arena.HomeTeam, "9", physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, units.BallMaxSpeed)))
+	// the same turn announced again is ignored
+	assert.Nil(t, tracker.Update(s.Snapshot()))
+
+	assert.Equal(t, []EventType{TouchEvent, PossessionEvent, ShotEvent, GoalEvent}, eventTypes(tracker.Events()))
+	goal := tracker.Events()[3]
+	assert.Equal(t, arena.HomeTeam, goal.Team)
+	assert.Equal(t, arena.PlayerNumber("9"), goal.Player)
+	assert.Equal(t, 1, tracker.Team(arena.HomeTeam).Shots)
+	assert.Equal(t, 1, tracker.Team(arena.HomeTeam).Goals)
+	assert.Equal(t, 1, tracker.Player(arena.HomeTeam, "9").Goals)
+
+	// the kickoff starts a new play
+	_, ok := tracker.LastTouch()
+	assert.False(t, ok)
+	assert.Equal(t, arena.TeamPlace(""), tracker.Possession())
+}
+
+func TestPossessionTracker_Deflection(t *testing.T) {
+	ball := arena.Ball{Element: physics.Element{Size: units.BallSize, Coords: physics.Point{PosX: 5000, PosY: 5000}, Velocity: physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, 200)}}
+	defender := arena.Player{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 5450, PosY: 5000}}, Number: "7", TeamPlace: arena.AwayTeam}
+	first := arena.Snapshot{Turn: 1, State: arena.Listening, Ball: ball, AwayTeam: arena.Team{Place: arena.AwayTeam, Players: []arena.Player{defender}}}
+
+	tracker := NewPossessionTracker()
+	assert.Empty(t, tracker.Update(first))
+
+	// the ball follows its path
+	second := first.Copy()
+	second.Turn = 2
+	second.Ball.Coords = physics.Point{PosX: 5200, PosY: 5000}
+	second.Ball.Velocity.Speed -= units.BallDeceleration
+	assert.Empty(t, tracker.Update(second))
+
+	// the ball hits the defender and goes back
+	third := second.Copy()
+	third.Turn = 3
+	third.Ball.Coords = physics.Point{PosX: 5100, PosY: 5000}
+	third.Ball.Velocity = physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: -1}, 100)
+	events := tracker.Update(third)
+	assert.Equal(t, []EventType{TouchEvent, PossessionEvent}, eventTypes(events))
+	assert.Equal(t, arena.PlayerNumber("7"), events[0].Player)
+	assert.Equal(t, arena.AwayTeam, tracker.Possession())
+}
+
+func TestPossessionTracker_TouchOnPath(t *testing.T) {
+	ball := arena.Ball{Element: physics.Element{Size: units.BallSize, Coords: physics.Point{PosX: 5000, PosY: 5000}, Velocity: physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, 200)}}
+	teammate := arena.Player{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 5200, PosY: 5250}}, Number: "8", TeamPlace: arena.HomeTeam}
+	first := arena.Snapshot{Turn: 1, State: arena.Listening, Ball: ball, HomeTeam: arena.Team{Place: arena.HomeTeam, Players: []arena.Player{teammate}}}
+
+	tracker := NewPossessionTracker()
+	assert.Empty(t, tracker.Update(first))
+
+	// the ball is where it was predicted, but the teammate slowed it down
+	second := first.Copy()
+	second.Turn = 2
+	second.Ball.Coords = physics.Point{PosX: 5200, PosY: 5000}
+	second.Ball.Velocity.Speed = 50
+	events := tracker.Update(second)
+	assert.Equal(t, []EventType{TouchEvent, PossessionEvent}, eventTypes(events))
+	assert.Equal(t, arena.PlayerNumber("8"), events[0].Player)
+
+	// the ball keeps decelerating as predicted
+	third := second.Copy()
+	third.Turn = 3
+	third.Ball.Coords = physics.Point{PosX: 5250, PosY: 5000}
+	third.Ball.Velocity.Speed -= units.BallDeceleration
+	assert.Empty(t, tracker.Update(third))
+}
+
+func TestPossessionTracker_Rules(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.BallDeceleration = 20
+	ball := arena.Ball{Element: physics.Element{Size: units.BallSize, Coords: physics.Point{PosX: 5000, PosY: 5000}, Velocity: physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, 200)}}
+	teammate := arena.Player{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 5200, PosY: 5250}}, Number: "8", TeamPlace: arena.HomeTeam}
+	first := arena.Snapshot{Turn: 1, State: arena.Listening, Ball: ball, HomeTeam: arena.Team{Place: arena.HomeTeam, Players: []arena.Player{teammate}}}
+	second := first.Copy()
+	second.Turn = 2
+	second.Ball.Coords = physics.Point{PosX: 5200, PosY: 5000}
+	second.Ball.Velocity.Speed = 180
+
+	// the ball decelerates as the match rules define
+	tracker := NewPossessionTrackerWithRules(rules)
+	tracker.Update(first)
+	assert.Empty(t, tracker.Update(second))
+
+	// the default rules would find a touch
+	tracker = NewPossessionTracker()
+	tracker.Update(first)
+	assert.Equal(t, []EventType{TouchEvent, PossessionEvent}, eventTypes(tracker.Update(second)))
+}
@@ -0,0 +1,167 @@
+package arena
+
+import (
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"time"
+)
+
+// MatchClock follows the game time from the announced snapshots. Besides the match time, it counts the turns of the
+// rules that are limited in turns: how long the ball has been in a goal zone (it is auto kicked after
+// BallTimeInGoalZone turns) and how many turns are left in the goalkeepers jumps (that take GoalKeeperJumpDuration
+// turns and cannot be interrupted).
+type MatchClock struct {
+	// Rules are the game values used to count the limited durations
+	Rules units.Rules
+	// TurnDuration is the time between two turns
+	TurnDuration time.Duration
+	// TotalTurns is the number of turns of the whole match
+	TotalTurns int
+	// Turn is the last announced turn
+	Turn int
+	// State is the last announced game state
+	State GameState
+
+	field         Field
+	updated       bool
+	goalZoneTurns map[TeamPlace]int
+	jumpTurns     map[TeamPlace]int
+	// goalkeepers has the goalkeepers coords in the last announced turn, to find the moves faster than running
+	goalkeepers map[TeamPlace]physics.Point
+}
+
+// NewMatchClock creates a clock for a match of `totalTurns` turns lasting `turnDuration` each
+func NewMatchClock(rules units.Rules, totalTurns int, turnDuration time.Duration) *MatchClock {
+	return &MatchClock{
+		Rules:         rules,
+		TurnDuration:  turnDuration,
+		TotalTurns:    totalTurns,
+		field:         NewFieldFromRules(rules),
+		goalZoneTurns: map[TeamPlace]int{},
+		jumpTurns:     map[TeamPlace]int{},
+		goalkeepers:   map[TeamPlace]physics.Point{},
+	}
+}
+
+// Update feeds the clock with the snapshot of an announcement. The counters only advance when the turn changes, so
+// the same turn may be announced more than once (e.g. in the listening and the playing states).
+// A goalkeeper is found jumping when it is faster than running or when it has moved farther than running allows since
+// the last announced turn, so the jumps that end before being announced are counted too. Jumps that are not faster
+// than running cannot be told apart in the snapshots, they are only counted when registered by StartJump.
+func (c *MatchClock) Update(snapshot Snapshot) {
+	elapsed := snapshot.Turn - c.Turn
+	newTurn := !c.updated || elapsed != 0
+	if !c.updated {
+		elapsed = 0
+	}
+	c.updated = true
+	c.Turn = snapshot.Turn
+	c.State = snapshot.State
+	if snapshot.State == Results || snapshot.State == Over {
+		// the ball goes back to the center after a goal, and nobody is jumping at the kickoff
+		c.goalZoneTurns = map[TeamPlace]int{}
+		c.jumpTurns = map[TeamPlace]int{}
+		c.goalkeepers = map[TeamPlace]physics.Point{}
+		return
+	}
+	if !newTurn {
+		return
+	}
+	for _, place := range []TeamPlace{HomeTeam, AwayTeam} {
+		if c.field.IsInGoalZone(place, snapshot.Ball.Coords) {
+			c.goalZoneTurns[place]++
+		} else {
+			c.goalZoneTurns[place] = 0
+		}
+
+		jumping := false
+		if goalkeeper := snapshot.Player(place, GoalkeeperNumber); goalkeeper != nil {
+			jumping = goalkeeper.Velocity.Speed > c.Rules.PlayerMaxSpeed || c.outran(place, goalkeeper.Coords, elapsed)
+			c.goalkeepers[place] = goalkeeper.Coords
+		} else {
+			delete(c.goalkeepers, place)
+		}
+		switch {
+		case c.jumpTurns[place] > 0:
+			c.jumpTurns[place]--
+		case jumping:
+			// the jump was not registered by StartJump, so this is its first turn
+			c.jumpTurns[place] = c.Rules.GoalKeeperJumpDuration - 1
+		}
+	}
+}
+
+// outran tells if the goalkeeper has moved farther than running allows in the elapsed turns. One unit is tolerated
+// because the coords are rounded.
+func (c *MatchClock) outran(place TeamPlace, coords physics.Point, elapsed int) bool {
+	previous, ok := c.goalkeepers[place]
+	if !ok || elapsed <= 0 {
+		return false
+	}
+	return previous.DistanceTo(coords) > c.Rules.PlayerMaxSpeed*float64(elapsed)+1
+}
+
+// UpdateFromMessage feeds the clock with the snapshot of the message. Only announcements should be used.
+func (c *MatchClock) UpdateFromMessage(msg GameMessage) {
+	c.Update(msg.Snapshot)
+}
+
+// Elapsed returns the match time until the last announced turn
+func (c *MatchClock) Elapsed() time.Duration {
+	return time.Duration(c.Turn) * c.TurnDuration
+}
+
+// RemainingTurns returns how many turns are left in the match
+func (c *MatchClock) RemainingTurns() int {
+	if c.Turn >= c.TotalTurns {
+		return 0
+	}
+	return c.TotalTurns - c.Turn
+}
+
+// Remaining returns the match time left
+func (c *MatchClock) Remaining() time.Duration {
+	return time.Duration(c.RemainingTurns()) * c.TurnDuration
+}
+
+// HalfTimeTurn is the last turn of the first half
+func (c *MatchClock) HalfTimeTurn() int {
+	return c.TotalTurns / 2
+}
+
+// IsSecondHalf tells if the last announced turn is after the half time
+func (c *MatchClock) IsSecondHalf() bool {
+	return c.Turn > c.HalfTimeTurn()
+}
+
+// BallTurnsInGoalZone returns for how many consecutive turns the ball has been in the goal zone in front of the goal
+// defended by the team
+func (c *MatchClock) BallTurnsInGoalZone(place TeamPlace) int {
+	return c.goalZoneTurns[place]
+}
+
+// TurnsBeforeAutoKick returns how many turns the ball may still stay in the goal zone of the team before being auto
+// kicked
+func (c *MatchClock) TurnsBeforeAutoKick(place TeamPlace) int {
+	left := c.Rules.BallTimeInGoalZone - c.goalZoneTurns[place]
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// StartJump registers a jump order sent to the goalkeeper of the team, so the counter is right even before the jump
+// is announced
+func (c *MatchClock) StartJump(place TeamPlace) {
+	c.jumpTurns[place] = c.Rules.GoalKeeperJumpDuration
+}
+
+// JumpTurnsLeft returns how many turns are left until the goalkeeper of the team may receive other orders
+func (c *MatchClock) JumpTurnsLeft(place TeamPlace) int {
+	return c.jumpTurns[place]
+}
+
+// IsJumping tells if the goalkeeper of the team is jumping
+func (c *MatchClock) IsJumping(place TeamPlace) bool {
+	return c.jumpTurns[place] > 0
+}
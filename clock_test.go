@@ -0,0 +1,120 @@
+package arena
+
+import (
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newTestClockSnapshot(turn int, ball physics.Point, goalkeeperSpeed float64) Snapshot {
+	goalkeeper := Player{Number: GoalkeeperNumber, TeamPlace: HomeTeam}
+	goalkeeper.Coords = HomeTeamGoal.Center
+	goalkeeper.Velocity = physics.NewZeroedVelocity(physics.North)
+	goalkeeper.Velocity.Speed = goalkeeperSpeed
+	snapshot := Snapshot{Turn: turn, State: Listening}
+	snapshot.Ball.Coords = ball
+	snapshot.HomeTeam = Team{Place: HomeTeam, Players: []Player{goalkeeper}}
+	return snapshot
+}
+
+func TestMatchClock_Time(t *testing.T) {
+	clock := NewMatchClock(units.DefaultRules(), 1000, 50*time.Millisecond)
+	clock.Update(newTestClockSnapshot(300, FieldCenter, 0))
+	assert.Equal(t, 15*time.Second, clock.Elapsed())
+	assert.Equal(t, 700, clock.RemainingTurns())
+	assert.Equal(t, 35*time.Second, clock.Remaining())
+	assert.Equal(t, 500, clock.HalfTimeTurn())
+	assert.False(t, clock.IsSecondHalf())
+
+	clock.Update(newTestClockSnapshot(501, FieldCenter, 0))
+	assert.True(t, clock.IsSecondHalf())
+
+	clock.UpdateFromMessage(GameMessage{Snapshot: newTestClockSnapshot(1200, FieldCenter, 0)})
+	assert.Equal(t, 0, clock.RemainingTurns())
+}
+
+func TestMatchClock_GoalZone(t *testing.T) {
+	clock := NewMatchClock(units.DefaultRules(), 1000, 50*time.Millisecond)
+	inZone := physics.Point{PosX: 500, PosY: units.FieldHeight / 2}
+
+	for turn := 1; turn <= 5; turn++ {
+		clock.Update(newTestClockSnapshot(turn, inZone, 0))
+	}
+	// announcing the same turn again does not count
+	clock.Update(newTestClockSnapshot(5, inZone, 0))
+	assert.Equal(t, 5, clock.BallTurnsInGoalZone(HomeTeam))
+	assert.Equal(t, 0, clock.BallTurnsInGoalZone(AwayTeam))
+	assert.Equal(t, units.BallTimeInGoalZone-5, clock.TurnsBeforeAutoKick(HomeTeam))
+	assert.Equal(t, units.BallTimeInGoalZone, clock.TurnsBeforeAutoKick(AwayTeam))
+
+	for turn := 6; turn <= 30; turn++ {
+		clock.Update(newTestClockSnapshot(turn, inZone, 0))
+	}
+	assert.Equal(t, 0, clock.TurnsBeforeAutoKick(HomeTeam))
+
+	clock.Update(newTestClockSnapshot(31, FieldCenter, 0))
+	assert.Equal(t, 0, clock.BallTurnsInGoalZone(HomeTeam))
+}
+
+func TestMatchClock_Jump(t *testing.T) {
+	clock := NewMatchClock(units.DefaultRules(), 1000, 50*time.Millisecond)
+	clock.Update(newTestClockSnapshot(1, FieldCenter, 0))
+	assert.False(t, clock.IsJumping(HomeTeam))
+
+	// a jump found in the announcement
+	clock.Update(newTestClockSnapshot(2, FieldCenter, units.GoalKeeperJumpSpeed))
+	assert.Equal(t, units.GoalKeeperJumpDuration-1, clock.JumpTurnsLeft(HomeTeam))
+	clock.Update(newTestClockSnapshot(3, FieldCenter, units.GoalKeeperJumpSpeed))
+	clock.Update(newTestClockSnapshot(4, FieldCenter, units.GoalKeeperJumpSpeed))
+	assert.False(t, clock.IsJumping(HomeTeam))
+	assert.False(t, clock.IsJumping(AwayTeam))
+
+	// a jump registered when the order is sent
+	clock.StartJump(HomeTeam)
+	assert.Equal(t, units.GoalKeeperJumpDuration, clock.JumpTurnsLeft(HomeTeam))
+	clock.Update(newTestClockSnapshot(5, FieldCenter, units.GoalKeeperJumpSpeed))
+	assert.Equal(t, units.GoalKeeperJumpDuration-1, clock.JumpTurnsLeft(HomeTeam))
+
+	// a goal resets the counters
+	results := newTestClockSnapshot(6, FieldCenter, 0)
+	results.State = Results
+	clock.Update(results)
+	assert.False(t, clock.IsJumping(HomeTeam))
+}
+
+func TestMatchClock_ShortSlowJump(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.GoalKeeperJumpDuration = 2
+	clock := NewMatchClock(rules, 1000, 50*time.Millisecond)
+	clock.Update(newTestClockSnapshot(1, FieldCenter, 0))
+
+	// the jump is slower than the max jump speed and it was over before the announcement, so the goalkeeper velocity
+	// is already zeroed, but it has moved farther than running allows
+	snapshot := newTestClockSnapshot(2, FieldCenter, 0)
+	snapshot.HomeTeam.Players[0].Coords.PosY += int(rules.PlayerMaxSpeed) + 50
+	clock.Update(snapshot)
+	assert.True(t, clock.IsJumping(HomeTeam))
+	assert.Equal(t, 1, clock.JumpTurnsLeft(HomeTeam))
+	assert.False(t, clock.IsJumping(AwayTeam))
+
+	// running is not jumping, even when some announcements are missed
+	clock = NewMatchClock(rules, 1000, 50*time.Millisecond)
+	clock.Update(newTestClockSnapshot(1, FieldCenter, 0))
+	snapshot = newTestClockSnapshot(2, FieldCenter, rules.PlayerMaxSpeed)
+	snapshot.HomeTeam.Players[0].Coords.PosY += int(rules.PlayerMaxSpeed)
+	clock.Update(snapshot)
+	assert.False(t, clock.IsJumping(HomeTeam))
+	snapshot = newTestClockSnapshot(4, FieldCenter, rules.PlayerMaxSpeed)
+	snapshot.HomeTeam.Players[0].Coords.PosY += int(3 * rules.PlayerMaxSpeed)
+	clock.Update(snapshot)
+	assert.False(t, clock.IsJumping(HomeTeam))
+
+	// the goalkeepers go back to their places after a goal
+	results := newTestClockSnapshot(5, FieldCenter, 0)
+	results.State = Results
+	clock.Update(results)
+	clock.Update(newTestClockSnapshot(6, FieldCenter, 0))
+	assert.False(t, clock.IsJumping(HomeTeam))
+}
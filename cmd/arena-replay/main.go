@@ -0,0 +1,235 @@
+// arena-replay inspects the replay files recorded by the replay package.
+//
+// Usage:
+//
+//	arena-replay [flags] <replay file>
+//
+// By default it prints the match summary: the score timeline, the ball possession, shots, passes, failed passes and
+// tackles of each team found by analysis.PossessionTracker, and the distance run by each player.
+// The events list may be filtered by team, player and game state.
+// The -turn flag dumps the messages and orders of a single turn as JSON, and the -verify flag re-simulates the match
+// to check that it followed the game rules. The -render flag plays the match in the terminal, starting at the -turn
+// turn when it is set, and the -no-color flag draws it without the terminal colors.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/render"
+	"github.com/lugobots/arena/replay"
+	"github.com/lugobots/arena/units"
+	"io"
+	"os"
+	"time"
+)
+
+type options struct {
+	turn   int
+	events bool
+	team   string
+	player string
+	state  string
+	json   bool
+	verify bool
+	seed   int64
+
+	render   bool
+	columns  int
+	arrows   bool
+	noColor  bool
+	interval time.Duration
+}
+
+func main() {
+	opts := options{}
+	flag.IntVar(&opts.turn, "turn", -1, "dumps the messages and orders of the turn as JSON")
+	flag.BoolVar(&opts.events, "events", false, "lists the match events")
+	flag.StringVar(&opts.team, "team", "", "only lists the events of the team (home or away)")
+	flag.StringVar(&opts.player, "player", "", "only lists the events involving the player number")
+	flag.StringVar(&opts.state, "state", "", "only lists the events that happened in the game state")
+	flag.BoolVar(&opts.json, "json", false, "prints the summary as JSON")
+	flag.BoolVar(&opts.verify, "verify", false, "re-simulates the match and reports the first divergence")
+	flag.Int64Var(&opts.seed, "seed", 0, "seed used by the simulator when verifying the match")
+	flag.BoolVar(&opts.render, "render", false, "plays the match in the terminal")
+	flag.IntVar(&opts.columns, "columns", render.DefaultColumns, "width of the field drawn by -render")
+	flag.BoolVar(&opts.arrows, "arrows", false, "draws the velocity arrows when rendering the match")
+	flag.BoolVar(&opts.noColor, "no-color", false, "disables the terminal colors when rendering the match")
+	flag.DurationVar(&opts.interval, "interval", 50*time.Millisecond, "time between the frames drawn by -render")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <replay file>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), opts, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(path string, opts options, out io.Writer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader, err := replay.NewReader(file)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case opts.verify:
+		return verify(reader, opts.seed, out)
+	case opts.render:
+		rules, err := reader.Header().MatchRules()
+		if err != nil {
+			return fmt.Errorf("the replay rules are invalid: %s", err)
+		}
+		renderer := render.NewASCIIWithRules(opts.columns, rules)
+		renderer.Arrows = opts.arrows
+		renderer.Colors = !opts.noColor
+		from := opts.turn
+		if from < 0 {
+			from = 0
+		}
+		return render.Play(reader, renderer, from, opts.interval, out)
+	case opts.turn >= 0:
+		return dumpTurn(reader, opts.turn, out)
+	}
+	summary, err := summarize(reader)
+	if err != nil {
+		return err
+	}
+	summary.Events = filterEvents(summary.Events, arena.TeamPlace(opts.team), arena.PlayerNumber(opts.player), arena.GameState(opts.state))
+	if opts.json {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summary)
+	}
+	printSummary(reader.Header(), summary, opts.events, out)
+	return nil
+}
+
+// summarize reads all announcements of the replay
+func summarize(reader *replay.Reader) (Summary, error) {
+	rules, err := reader.Header().MatchRules()
+	if err != nil {
+		return Summary{}, fmt.Errorf("the replay rules are invalid: %s", err)
+	}
+	s := newSummarizer(rules)
+	for {
+		entry, err := reader.Next()
+		if err == io.EOF {
+			return s.result(), nil
+		}
+		if err != nil {
+			return Summary{}, err
+		}
+		if entry.Kind != replay.MessageEntry {
+			continue
+		}
+		msg, err := entry.GameMessage()
+		if err != nil {
+			return Summary{}, err
+		}
+		if msg.Type == orders.ANNOUNCEMENT {
+			s.add(msg.Snapshot)
+		}
+	}
+}
+
+// turnDump is the content of a turn in the replay
+type turnDump struct {
+	Turn     int               `json:"turn"`
+	Messages []json.RawMessage `json:"messages"`
+	Orders   []orders.Batch    `json:"orders"`
+}
+
+func dumpTurn(reader *replay.Reader, turn int, out io.Writer) error {
+	if err := reader.SeekTurn(turn); err != nil {
+		return err
+	}
+	dump := turnDump{Turn: turn, Messages: []json.RawMessage{}, Orders: []orders.Batch{}}
+	for {
+		entry, err := reader.Next()
+		if err == io.EOF || (err == nil && entry.Turn > turn) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch entry.Kind {
+		case replay.MessageEntry:
+			dump.Messages = append(dump.Messages, entry.Message)
+		case replay.OrdersEntry:
+			dump.Orders = append(dump.Orders, entry.Batches...)
+		}
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dump)
+}
+
+func verify(reader *replay.Reader, seed int64, out io.Writer) error {
+	divergence, err := replay.Verify(reader, replay.DefaultTolerance, seed)
+	if err != nil {
+		return err
+	}
+	if divergence != nil {
+		return fmt.Errorf("the match diverges from the simulation: %s", divergence)
+	}
+	fmt.Fprintln(out, "the match follows the game rules")
+	return nil
+}
+
+func printSummary(header replay.Header, summary Summary, withEvents bool, out io.Writer) {
+	fmt.Fprintf(out, "%s x %s (%d turns)\n", header.HomeTeam, header.AwayTeam, summary.Turns)
+
+	fmt.Fprintln(out, "\nScore:")
+	if len(summary.Timeline) == 0 {
+		fmt.Fprintln(out, "  no goals")
+	}
+	for _, change := range summary.Timeline {
+		fmt.Fprintf(out, "  turn %5d: %d x %d\n", change.Turn, change.Home, change.Away)
+	}
+
+	held := summary.Teams[arena.HomeTeam].PossessionTurns + summary.Teams[arena.AwayTeam].PossessionTurns
+	fmt.Fprintln(out, "\nTeam stats:")
+	for _, place := range []arena.TeamPlace{arena.HomeTeam, arena.AwayTeam} {
+		stats := summary.Teams[place]
+		share := 0.0
+		if held > 0 {
+			share = 100 * float64(stats.PossessionTurns) / float64(held)
+		}
+		fmt.Fprintf(out, "  %-5s possession %5.1f%%  shots %3d  passes %3d  failed passes %3d  tackles %3d\n", place, share, stats.Shots, stats.Passes, stats.FailedPasses, stats.Tackles)
+	}
+
+	rules, err := header.MatchRules()
+	if err != nil {
+		rules = units.DefaultRules()
+	}
+	scale := units.NewScale(rules, units.DefaultPitchLength, units.DefaultTurnDuration)
+	fmt.Fprintln(out, "\nDistance run:")
+	for _, player := range sortedKeys(summary.Distance) {
+		distance := units.Distance(summary.Distance[player])
+		fmt.Fprintf(out, "  %-8s %8.0f  %10s\n", player, float64(distance), scale.FormatDistance(distance))
+	}
+
+	if withEvents {
+		fmt.Fprintln(out, "\nEvents:")
+		for _, event := range summary.Events {
+			fmt.Fprintf(out, "  turn %5d [%s] %s %s %s", event.Turn, event.State, event.Type, event.Team, event.Player)
+			if event.Other != "" {
+				fmt.Fprintf(out, " -> %s", event.Other)
+			}
+			fmt.Fprintln(out)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/analysis"
+	"github.com/lugobots/arena/units"
+	"sort"
+)
+
+// ScoreChange is a point in the score timeline
+type ScoreChange struct {
+	Turn int `json:"turn"`
+	Home int `json:"home"`
+	Away int `json:"away"`
+}
+
+// Summary are the statistics of a match. The stats and the events are found by the possession tracker (see
+// analysis.PossessionTracker).
+type Summary struct {
+	Turns int `json:"turns"`
+	// Timeline has the score after each goal
+	Timeline []ScoreChange `json:"timeline"`
+	// Teams are the stats of each team
+	Teams map[arena.TeamPlace]analysis.Stats `json:"teams"`
+	// Players are the stats of each player, identified by "place/number"
+	Players map[string]analysis.Stats `json:"players"`
+	// Distance is the distance run by each player, identified by "place/number"
+	Distance map[string]float64 `json:"distance"`
+	// Events are all events found in the match
+	Events []analysis.Event `json:"events"`
+}
+
+// summarizer builds the summary from consecutive announcements
+type summarizer struct {
+	summary  Summary
+	previous *arena.Snapshot
+	tracker  *analysis.PossessionTracker
+}
+
+func newSummarizer(rules units.Rules) *summarizer {
+	return &summarizer{
+		summary: Summary{Distance: map[string]float64{}},
+		tracker: analysis.NewPossessionTrackerWithRules(rules),
+	}
+}
+
+// add feeds the summarizer with the next announcement
+func (s *summarizer) add(snapshot arena.Snapshot) {
+	s.summary.Turns = snapshot.Turn
+	s.tracker.Update(snapshot)
+	if s.previous == nil {
+		s.previous = &snapshot
+		return
+	}
+	previous := s.previous
+
+	if snapshot.HomeTeam.Score != previous.HomeTeam.Score || snapshot.AwayTeam.Score != previous.AwayTeam.Score {
+		s.summary.Timeline = append(s.summary.Timeline, ScoreChange{Turn: snapshot.Turn, Home: snapshot.HomeTeam.Score, Away: snapshot.AwayTeam.Score})
+	}
+
+	// the players are moved back to their initial positions after a goal
+	if snapshot.State != arena.Results {
+		for _, player := range snapshot.Players() {
+			if before := previous.Player(player.TeamPlace, player.Number); before != nil {
+				s.summary.Distance[string(player.TeamPlace)+"/"+string(player.Number)] += before.Coords.DistanceTo(player.Coords)
+			}
+		}
+	}
+	s.previous = &snapshot
+}
+
+// result completes the summary with the possession tracker stats and events
+func (s *summarizer) result() Summary {
+	summary := s.summary
+	summary.Teams = map[arena.TeamPlace]analysis.Stats{
+		arena.HomeTeam: s.tracker.Team(arena.HomeTeam),
+		arena.AwayTeam: s.tracker.Team(arena.AwayTeam),
+	}
+	summary.Players = map[string]analysis.Stats{}
+	for id, stats := range s.tracker.Players() {
+		summary.Players[id.String()] = stats
+	}
+	summary.Events = append([]analysis.Event{}, s.tracker.Events()...)
+	return summary
+}
+
+// filterEvents returns the events that match all the non empty filters
+func filterEvents(events []analysis.Event, team arena.TeamPlace, player arena.PlayerNumber, state arena.GameState) []analysis.Event {
+	var filtered []analysis.Event
+	for _, event := range events {
+		if team != "" && event.Team != team {
+			continue
+		}
+		if player != "" && event.Player != player && event.Other != player {
+			continue
+		}
+		if state != "" && event.State != state {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered
+}
+
+// sortedKeys returns the map keys in alphabetical order
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/analysis"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/sim"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func holding(snapshot arena.Snapshot, place arena.TeamPlace, number arena.PlayerNumber) arena.Snapshot {
+	holder := snapshot.Player(place, number).Copy()
+	snapshot.Ball.Holder = &holder
+	snapshot.Ball.Coords = holder.Coords
+	return snapshot
+}
+
+func TestSummarizer_PassesAndShots(t *testing.T) {
+	initial := sim.New(sim.Config{}).Snapshot()
+	s := newSummarizer(units.DefaultRules())
+
+	next := func(change func(snapshot *arena.Snapshot)) {
+		snapshot := s.previous.Copy()
+		snapshot.Ball.Holder = nil
+		snapshot.Turn++
+		snapshot.State = arena.Listening
+		change(&snapshot)
+		s.add(snapshot)
+	}
+	s.add(initial)
+	next(func(snapshot *arena.Snapshot) { *snapshot = holding(*snapshot, arena.HomeTeam, "7") })
+	next(func(snapshot *arena.Snapshot) {
+		snapshot.Player(arena.HomeTeam, "7").Coords.PosX += 100
+		*snapshot = holding(*snapshot, arena.HomeTeam, "7")
+	})
+	// released towards the teammate
+	next(func(snapshot *arena.Snapshot) {})
+	next(func(snapshot *arena.Snapshot) { *snapshot = holding(*snapshot, arena.HomeTeam, "10") })
+	// shot towards the away goal
+	next(func(snapshot *arena.Snapshot) {
+		snapshot.Ball.Coords = physics.Point{PosX: units.FieldWidth - 3000, PosY: units.FieldHeight / 2}
+		snapshot.Ball.Velocity = physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, units.BallMaxSpeed)
+	})
+	next(func(snapshot *arena.Snapshot) {
+		snapshot.HomeTeam.Score = 1
+		snapshot.State = arena.Results
+		snapshot.Player(arena.HomeTeam, "7").Coords.PosX -= 100
+	})
+
+	summary := s.result()
+	assert.Equal(t, 6, summary.Turns)
+	assert.Equal(t, []ScoreChange{{Turn: 6, Home: 1, Away: 0}}, summary.Timeline)
+	// the team has the ball since the first catch until the goal
+	assert.Equal(t, 5, summary.Teams[arena.HomeTeam].PossessionTurns)
+	assert.Equal(t, 0, summary.Teams[arena.AwayTeam].PossessionTurns)
+	assert.Equal(t, 1, summary.Teams[arena.HomeTeam].Passes)
+	assert.Equal(t, 1, summary.Teams[arena.HomeTeam].Shots)
+	assert.Equal(t, 1, summary.Teams[arena.HomeTeam].Goals)
+	assert.Equal(t, 2, summary.Players["home/7"].PossessionTurns)
+	assert.Equal(t, 1, summary.Players["home/7"].Passes)
+	assert.Equal(t, 1, summary.Players["home/10"].Goals)
+	// the reset after the goal is not counted
+	assert.Equal(t, 100.0, summary.Distance["home/7"])
+	assert.Equal(t, 0.0, summary.Distance["away/7"])
+
+	types := []analysis.EventType{}
+	for _, event := range summary.Events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []analysis.EventType{analysis.TouchEvent, analysis.PossessionEvent, analysis.TouchEvent, analysis.PassEvent, analysis.ShotEvent, analysis.GoalEvent}, types)
+	assert.Equal(t, arena.PlayerNumber("7"), summary.Events[3].Player)
+	assert.Equal(t, arena.PlayerNumber("10"), summary.Events[3].Other)
+}
+
+func TestSummarizer_Interception(t *testing.T) {
+	snapshot := sim.New(sim.Config{}).Snapshot()
+	s := newSummarizer(units.DefaultRules())
+	s.add(holding(snapshot, arena.HomeTeam, "7"))
+	snapshot.Turn = 1
+	s.add(snapshot)
+	snapshot.Turn = 2
+	s.add(holding(snapshot, arena.AwayTeam, "7"))
+
+	summary := s.result()
+	assert.Equal(t, 0, summary.Teams[arena.HomeTeam].Passes)
+	assert.Equal(t, 0, summary.Teams[arena.HomeTeam].Shots)
+	assert.Equal(t, 1, summary.Teams[arena.HomeTeam].FailedPasses)
+	assert.Equal(t, 1, summary.Players["home/7"].FailedPasses)
+	last := summary.Events[len(summary.Events)-1]
+	assert.Equal(t, analysis.PossessionEvent, last.Type)
+	assert.Equal(t, arena.AwayTeam, last.Team)
+}
+
+func TestFilterEvents(t *testing.T) {
+	events := []analysis.Event{
+		{Turn: 1, State: arena.Listening, Type: analysis.TouchEvent, Team: arena.HomeTeam, Player: "7"},
+		{Turn: 2, State: arena.Listening, Type: analysis.PassEvent, Team: arena.HomeTeam, Player: "7", Other: "10"},
+		{Turn: 3, State: arena.Results, Type: analysis.GoalEvent, Team: arena.AwayTeam},
+	}
+	assert.Len(t, filterEvents(events, "", "", ""), 3)
+	assert.Equal(t, events[:2], filterEvents(events, arena.HomeTeam, "", ""))
+	assert.Equal(t, events[1:2], filterEvents(events, "", "10", ""))
+	assert.Equal(t, events[2:], filterEvents(events, "", "", arena.Results))
+	assert.Empty(t, filterEvents(events, arena.AwayTeam, "7", ""))
+}
@@ -20,11 +20,11 @@ var HomeTeamGoal = Goal{
 
 // AwayTeamGoal works as a constant value to help to retrieve a Goal struct with the values of the Away team goal
 var AwayTeamGoal = Goal{
-	Place:      HomeTeam,
+	Place:      AwayTeam,
 	Center:     physics.Point{PosX: units.FieldWidth, PosY: units.FieldHeight / 2},
 	TopPole:    physics.Point{PosX: units.FieldWidth, PosY: units.GoalMaxY},
 	BottomPole: physics.Point{PosX: units.FieldWidth, PosY: units.GoalMinY},
 }
 
 // FieldCenter works as a constant value to help to retrieve a Point struct with the values of the center of the court
-var FieldCenter = physics.Point{units.FieldWidth / 2, units.FieldHeight / 2}
+var FieldCenter = physics.Point{PosX: units.FieldWidth / 2, PosY: units.FieldHeight / 2}
@@ -0,0 +1,228 @@
+package arena
+
+import (
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+)
+
+// RegionName identifies a named region of the field
+type RegionName string
+
+const (
+	// HomeHalfRegion is the half of the field defended by the home team
+	HomeHalfRegion RegionName = "home-half"
+	// AwayHalfRegion is the half of the field defended by the away team
+	AwayHalfRegion RegionName = "away-half"
+	// NeutralCenterRegion is the neutral circle on the center of the field
+	NeutralCenterRegion RegionName = "neutral-center"
+	// HomeGoalZoneRegion is the goal zone in front of the home team goal
+	HomeGoalZoneRegion RegionName = "home-goal-zone"
+	// AwayGoalZoneRegion is the goal zone in front of the away team goal
+	AwayGoalZoneRegion RegionName = "away-goal-zone"
+)
+
+// regionNames keeps the regions in a stable order to be used when listing them
+var regionNames = []RegionName{
+	HomeHalfRegion,
+	AwayHalfRegion,
+	NeutralCenterRegion,
+	HomeGoalZoneRegion,
+	AwayGoalZoneRegion,
+}
+
+// Region is an area of the field that may contain points
+type Region interface {
+	// Contains returns true when the point is inside the region (borders included)
+	Contains(p physics.Point) bool
+}
+
+// Segment is a straight line between two points
+type Segment struct {
+	A physics.Point
+	B physics.Point
+}
+
+// Rect is a rectangular region defined by its lowest and highest corners
+type Rect struct {
+	Min physics.Point
+	Max physics.Point
+}
+
+// Contains returns true when the point is inside the rectangle
+func (r Rect) Contains(p physics.Point) bool {
+	return p.PosX >= r.Min.PosX && p.PosX <= r.Max.PosX && p.PosY >= r.Min.PosY && p.PosY <= r.Max.PosY
+}
+
+// Circle is a circular region
+type Circle struct {
+	Center physics.Point
+	Radius int
+}
+
+// Contains returns true when the point is inside the circle
+func (c Circle) Contains(p physics.Point) bool {
+	return c.Center.DistanceTo(p) <= float64(c.Radius)
+}
+
+// GoalZone is the region close to a goal. A point is in the goal zone when its distance to the goal mouth is
+// not greater than the zone range.
+type GoalZone struct {
+	Goal  Goal
+	Range int
+	// field limits the zone to the points inside the field
+	field Rect
+}
+
+// Contains returns true when the point is inside the goal zone
+func (z GoalZone) Contains(p physics.Point) bool {
+	if !z.field.Contains(p) {
+		return false
+	}
+	return p.DistanceToSegment(z.Goal.BottomPole, z.Goal.TopPole) <= float64(z.Range)
+}
+
+// Field describes the geometry of the field
+type Field struct {
+	// Width is the field size in the X axis
+	Width int
+	// Height is the field size in the Y axis
+	Height int
+	// Center is the center point of the field
+	Center physics.Point
+	// NeutralCenterRadius is the radius of the neutral circle on the center of the field
+	NeutralCenterRadius int
+	// GoalZoneRange is the distance from the goal mouth that delimits the goal zones
+	GoalZoneRange int
+	// PlayerSize is the diameter of the players bodies
+	PlayerSize int
+	// HomeGoal is the goal defended by the home team
+	HomeGoal Goal
+	// AwayGoal is the goal defended by the away team
+	AwayGoal Goal
+}
+
+// NewField creates a field based on the units constants
+func NewField() Field {
+	return NewFieldFromRules(units.DefaultRules())
+}
+
+// NewFieldFromRules creates a field based on the rules
+func NewFieldFromRules(rules units.Rules) Field {
+	center := physics.Point{PosX: rules.FieldWidth / 2, PosY: rules.FieldHeight / 2}
+	return Field{
+		Width:               rules.FieldWidth,
+		Height:              rules.FieldHeight,
+		Center:              center,
+		NeutralCenterRadius: rules.FieldNeutralCenter,
+		GoalZoneRange:       rules.GoalZoneRange,
+		PlayerSize:          rules.PlayerSize,
+		HomeGoal: Goal{
+			Place:      HomeTeam,
+			Center:     physics.Point{PosX: 0, PosY: center.PosY},
+			TopPole:    physics.Point{PosX: 0, PosY: rules.GoalMaxY()},
+			BottomPole: physics.Point{PosX: 0, PosY: rules.GoalMinY()},
+		},
+		AwayGoal: Goal{
+			Place:      AwayTeam,
+			Center:     physics.Point{PosX: rules.FieldWidth, PosY: center.PosY},
+			TopPole:    physics.Point{PosX: rules.FieldWidth, PosY: rules.GoalMaxY()},
+			BottomPole: physics.Point{PosX: rules.FieldWidth, PosY: rules.GoalMinY()},
+		},
+	}
+}
+
+// Bounds returns the rectangle delimited by the field borders
+func (f Field) Bounds() Rect {
+	return Rect{Max: physics.Point{PosX: f.Width, PosY: f.Height}}
+}
+
+// Contains returns true when the point is inside the field borders
+func (f Field) Contains(p physics.Point) bool {
+	return f.Bounds().Contains(p)
+}
+
+// Borders returns the four field borders in the order top, right, bottom, left
+func (f Field) Borders() []Segment {
+	bottomLeft := physics.Point{PosX: 0, PosY: 0}
+	bottomRight := physics.Point{PosX: f.Width, PosY: 0}
+	topLeft := physics.Point{PosX: 0, PosY: f.Height}
+	topRight := physics.Point{PosX: f.Width, PosY: f.Height}
+	return []Segment{
+		{A: topLeft, B: topRight},
+		{A: bottomRight, B: topRight},
+		{A: bottomLeft, B: bottomRight},
+		{A: bottomLeft, B: topLeft},
+	}
+}
+
+// Goal returns the goal defended by the team
+func (f Field) Goal(place TeamPlace) Goal {
+	if place == AwayTeam {
+		return f.AwayGoal
+	}
+	return f.HomeGoal
+}
+
+// GoalMouth returns the segment between the poles of the goal defended by the team
+func (f Field) GoalMouth(place TeamPlace) Segment {
+	goal := f.Goal(place)
+	return Segment{A: goal.BottomPole, B: goal.TopPole}
+}
+
+// Half returns the half of the field defended by the team. The center line belongs to both halves.
+func (f Field) Half(place TeamPlace) Rect {
+	if place == AwayTeam {
+		return Rect{
+			Min: physics.Point{PosX: f.Center.PosX, PosY: 0},
+			Max: physics.Point{PosX: f.Width, PosY: f.Height},
+		}
+	}
+	return Rect{
+		Min: physics.Point{PosX: 0, PosY: 0},
+		Max: physics.Point{PosX: f.Center.PosX, PosY: f.Height},
+	}
+}
+
+// NeutralCenter returns the neutral circle on the center of the field
+func (f Field) NeutralCenter() Circle {
+	return Circle{Center: f.Center, Radius: f.NeutralCenterRadius}
+}
+
+// GoalZone returns the goal zone in front of the goal defended by the team
+func (f Field) GoalZone(place TeamPlace) GoalZone {
+	return GoalZone{Goal: f.Goal(place), Range: f.GoalZoneRange, field: f.Bounds()}
+}
+
+// Region returns the region identified by the name. The second value is false when the name is unknown.
+func (f Field) Region(name RegionName) (Region, bool) {
+	switch name {
+	case HomeHalfRegion:
+		return f.Half(HomeTeam), true
+	case AwayHalfRegion:
+		return f.Half(AwayTeam), true
+	case NeutralCenterRegion:
+		return f.NeutralCenter(), true
+	case HomeGoalZoneRegion:
+		return f.GoalZone(HomeTeam), true
+	case AwayGoalZoneRegion:
+		return f.GoalZone(AwayTeam), true
+	}
+	return nil, false
+}
+
+// RegionsAt lists the names of all regions that contain the point
+func (f Field) RegionsAt(p physics.Point) []RegionName {
+	var names []RegionName
+	for _, name := range regionNames {
+		region, _ := f.Region(name)
+		if region.Contains(p) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// IsInGoalZone returns true when the point is in the goal zone in front of the goal defended by the team
+func (f Field) IsInGoalZone(place TeamPlace, p physics.Point) bool {
+	return f.GoalZone(place).Contains(p)
+}
@@ -0,0 +1,88 @@
+package arena
+
+import (
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestField_Contains(t *testing.T) {
+	field := NewField()
+	assert.True(t, field.Contains(physics.Point{}))
+	assert.True(t, field.Contains(physics.Point{PosX: units.FieldWidth, PosY: units.FieldHeight}))
+	assert.False(t, field.Contains(physics.Point{PosX: -1, PosY: 10}))
+	assert.False(t, field.Contains(physics.Point{PosX: 10, PosY: units.FieldHeight + 1}))
+}
+
+func TestField_Half(t *testing.T) {
+	field := NewField()
+	assert.True(t, field.Half(HomeTeam).Contains(physics.Point{PosX: 10, PosY: 10}))
+	assert.False(t, field.Half(AwayTeam).Contains(physics.Point{PosX: 10, PosY: 10}))
+	assert.True(t, field.Half(AwayTeam).Contains(physics.Point{PosX: units.FieldWidth - 10, PosY: 10}))
+
+	// the center line belongs to both halves
+	assert.True(t, field.Half(HomeTeam).Contains(FieldCenter))
+	assert.True(t, field.Half(AwayTeam).Contains(FieldCenter))
+}
+
+func TestField_GoalZone(t *testing.T) {
+	field := NewField()
+	table := map[string]struct {
+		place    TeamPlace
+		point    physics.Point
+		expected bool
+	}{
+		"in front of the home goal":      {HomeTeam, physics.Point{PosX: units.GoalZoneRange, PosY: units.FieldHeight / 2}, true},
+		"too far from the home goal":     {HomeTeam, physics.Point{PosX: units.GoalZoneRange + 1, PosY: units.FieldHeight / 2}, false},
+		"close to the home top pole":     {HomeTeam, physics.Point{PosX: 0, PosY: units.GoalMaxY + units.GoalZoneRange}, true},
+		"beyond the home top pole range": {HomeTeam, physics.Point{PosX: 0, PosY: units.GoalMaxY + units.GoalZoneRange + 1}, false},
+		"home point in away zone":        {AwayTeam, physics.Point{PosX: units.GoalZoneRange, PosY: units.FieldHeight / 2}, false},
+		"in front of the away goal":      {AwayTeam, physics.Point{PosX: units.FieldWidth - 10, PosY: units.GoalMinY}, true},
+		"behind the away goal line":      {AwayTeam, physics.Point{PosX: units.FieldWidth + 1, PosY: units.FieldHeight / 2}, false},
+	}
+	for title, set := range table {
+		assert.Equal(t, set.expected, field.IsInGoalZone(set.place, set.point), title)
+	}
+}
+
+func TestField_GoalMouth(t *testing.T) {
+	field := NewField()
+	mouth := field.GoalMouth(AwayTeam)
+	assert.Equal(t, physics.Point{PosX: units.FieldWidth, PosY: units.GoalMinY}, mouth.A)
+	assert.Equal(t, physics.Point{PosX: units.FieldWidth, PosY: units.GoalMaxY}, mouth.B)
+}
+
+func TestField_Goal(t *testing.T) {
+	field := NewField()
+	assert.Equal(t, AwayTeam, field.Goal(AwayTeam).Place)
+	assert.Equal(t, HomeTeam, field.Goal(HomeTeam).Place)
+	assert.Equal(t, AwayTeam, AwayTeamGoal.Place)
+}
+
+func TestField_RegionsAt(t *testing.T) {
+	field := NewField()
+	assert.Equal(t, []RegionName{HomeHalfRegion, AwayHalfRegion, NeutralCenterRegion}, field.RegionsAt(FieldCenter))
+	assert.Equal(t, []RegionName{HomeHalfRegion, HomeGoalZoneRegion}, field.RegionsAt(HomeTeamGoal.Center))
+	assert.Nil(t, field.RegionsAt(physics.Point{PosX: -10, PosY: -10}))
+
+	_, ok := field.Region("unknown")
+	assert.False(t, ok)
+}
+
+func TestNewFieldFromRules(t *testing.T) {
+	assert.Equal(t, NewField(), NewFieldFromRules(units.DefaultRules()))
+
+	rules := units.DefaultRules()
+	rules.FieldWidth = 30000
+	rules.FieldHeight = 16000
+	rules.GoalWidth = 4000
+	rules.PlayerSize = 600
+	field := NewFieldFromRules(rules)
+	assert.Equal(t, 600, field.PlayerSize)
+	assert.Equal(t, physics.Point{PosX: 15000, PosY: 8000}, field.Center)
+	assert.Equal(t, physics.Point{PosX: 30000, PosY: 10000}, field.AwayGoal.TopPole)
+	assert.Equal(t, physics.Point{PosX: 0, PosY: 6000}, field.HomeGoal.BottomPole)
+	assert.True(t, field.IsInGoalZone(AwayTeam, physics.Point{PosX: 29000, PosY: 10500}))
+	assert.False(t, field.Contains(physics.Point{PosX: 30001, PosY: 8000}))
+}
@@ -0,0 +1,137 @@
+package formations
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"math"
+)
+
+// Shape defines how the team block moves and changes its size according to the ball position and the possession.
+// The lengths and widths are fractions of the field size.
+type Shape struct {
+	// AttackingLength is the distance between the last defender and the first forward when the team has the ball
+	AttackingLength float64
+	// DefendingLength is the distance between the last defender and the first forward when the team does not have
+	// the ball
+	DefendingLength float64
+	// AttackingWidth is the distance between the players on the wings when the team has the ball
+	AttackingWidth float64
+	// DefendingWidth is the distance between the players on the wings when the team does not have the ball
+	DefendingWidth float64
+	// AttackingOffset is how far the block center is behind the ball when the team has the ball
+	AttackingOffset float64
+	// DefendingOffset is how far the block center is behind the ball when the team does not have the ball
+	DefendingOffset float64
+	// BallAttraction is how much the block moves towards the ball side (0 keeps it on the field center line, 1 puts it
+	// on the ball Y coordinate)
+	BallAttraction float64
+	// GoalkeeperAttraction is how much the goalkeeper follows the ball between the poles (0 keeps it on the goal
+	// center, 1 puts it on the ball Y coordinate)
+	GoalkeeperAttraction float64
+	// RegionRadius is the radius of the target region of each player, in field units
+	RegionRadius int
+}
+
+// DefaultShape compresses the team to a short and narrow block when defending, and stretches it when attacking
+var DefaultShape = Shape{
+	AttackingLength:      0.55,
+	DefendingLength:      0.3,
+	AttackingWidth:       0.85,
+	DefendingWidth:       0.55,
+	AttackingOffset:      0.1,
+	DefendingOffset:      0.15,
+	BallAttraction:       0.4,
+	GoalkeeperAttraction: 0.5,
+	RegionRadius:         3 * units.PlayerSize,
+}
+
+// Targets finds the region each player of the team should occupy in the current turn using the default shape
+func (f Formation) Targets(field arena.Field, place arena.TeamPlace, ball physics.Point, possession bool) map[arena.PlayerNumber]arena.Circle {
+	return DefaultShape.Targets(f, field, place, ball, possession)
+}
+
+// Targets finds the region each player of the team should occupy in the current turn. The formation positions are
+// fitted in a block that follows the ball, whose size depends on the team having the ball (`possession`). The
+// goalkeeper stays on the goal line following the ball between the poles. No target is placed in the opponent goal
+// zone, where the players are not allowed.
+func (s Shape) Targets(f Formation, field arena.Field, place arena.TeamPlace, ball physics.Point, possession bool) map[arena.PlayerNumber]arena.Circle {
+	mirror := arena.Mirror(place)
+	relativeBall := mirror.PointIn(field, ball)
+	width, height := float64(field.Width), float64(field.Height)
+
+	length, spread, offset := s.DefendingLength, s.DefendingWidth, s.DefendingOffset
+	if possession {
+		length, spread, offset = s.AttackingLength, s.AttackingWidth, s.AttackingOffset
+	}
+	length *= width
+	blockX := clamp(float64(relativeBall.PosX)-offset*width, length/2, width-length/2)
+	blockY := height/2 + (float64(relativeBall.PosY)-height/2)*s.BallAttraction
+
+	minX, maxX := f.outfieldDepth()
+	radius := float64(field.PlayerSize) / 2
+	targets := make(map[arena.PlayerNumber]arena.Circle, len(f.Positions))
+	for number, position := range f.Positions {
+		var target physics.Point
+		if number == arena.GoalkeeperNumber {
+			goal := field.Goal(arena.HomeTeam)
+			target = physics.Point{
+				PosX: int(math.Round(position.X * width)),
+				PosY: int(math.Round(clamp(height/2+(float64(relativeBall.PosY)-height/2)*s.GoalkeeperAttraction, float64(goal.BottomPole.PosY), float64(goal.TopPole.PosY)))),
+			}
+		} else {
+			depth := 0.5
+			if maxX > minX {
+				depth = (position.X - minX) / (maxX - minX)
+			}
+			target = physics.Point{
+				PosX: int(math.Round(clamp(blockX-length/2+depth*length, radius, width-radius))),
+				PosY: int(math.Round(clamp(blockY+(position.Y-0.5)*spread*height, radius, height-radius))),
+			}
+			target = keepOutOfGoalZone(field, target, radius)
+		}
+		targets[number] = arena.Circle{Center: mirror.PointIn(field, target), Radius: s.RegionRadius}
+	}
+	return targets
+}
+
+// outfieldDepth finds the lowest and highest X of the players, except the goalkeeper
+func (f Formation) outfieldDepth() (float64, float64) {
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for number, position := range f.Positions {
+		if number == arena.GoalkeeperNumber {
+			continue
+		}
+		minX = math.Min(minX, position.X)
+		maxX = math.Max(maxX, position.X)
+	}
+	return minX, maxX
+}
+
+// keepOutOfGoalZone moves a point in the home team frame backwards until a player body there does not touch the
+// opponent goal zone
+func keepOutOfGoalZone(field arena.Field, p physics.Point, radius float64) physics.Point {
+	goal := field.Goal(arena.AwayTeam)
+	reach := float64(field.GoalZoneRange) + radius
+	dy := 0.0
+	if p.PosY < goal.BottomPole.PosY {
+		dy = float64(goal.BottomPole.PosY - p.PosY)
+	} else if p.PosY > goal.TopPole.PosY {
+		dy = float64(p.PosY - goal.TopPole.PosY)
+	}
+	if dy >= reach {
+		return p
+	}
+	limit := float64(goal.Center.PosX) - math.Sqrt(reach*reach-dy*dy)
+	if float64(p.PosX) > limit {
+		p.PosX = int(math.Floor(limit))
+	}
+	return p
+}
+
+func clamp(value, min, max float64) float64 {
+	if max < min {
+		return (min + max) / 2
+	}
+	return math.Max(min, math.Min(value, max))
+}
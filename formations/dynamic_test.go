@@ -0,0 +1,119 @@
+package formations
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+// blockSize finds the length and the width of the area occupied by the outfield players
+func blockSize(targets map[arena.PlayerNumber]arena.Circle) (int, int) {
+	minX, maxX, minY, maxY := math.MaxInt32, math.MinInt32, math.MaxInt32, math.MinInt32
+	for number, target := range targets {
+		if number == arena.GoalkeeperNumber {
+			continue
+		}
+		minX, maxX = min(minX, target.Center.PosX), max(maxX, target.Center.PosX)
+		minY, maxY = min(minY, target.Center.PosY), max(maxY, target.Center.PosY)
+	}
+	return maxX - minX, maxY - minY
+}
+
+func meanX(targets map[arena.PlayerNumber]arena.Circle) float64 {
+	sum := 0
+	for _, target := range targets {
+		sum += target.Center.PosX
+	}
+	return float64(sum) / float64(len(targets))
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func TestFormation_Targets_Possession(t *testing.T) {
+	field := arena.NewField()
+	attacking := FourFourTwo.Targets(field, arena.HomeTeam, arena.FieldCenter, true)
+	defending := FourFourTwo.Targets(field, arena.HomeTeam, arena.FieldCenter, false)
+	assert.Len(t, attacking, 11)
+
+	attackingLength, attackingWidth := blockSize(attacking)
+	defendingLength, defendingWidth := blockSize(defending)
+	assert.Equal(t, 11000, attackingLength)
+	assert.Equal(t, 6000, defendingLength)
+	assert.Equal(t, 5950, attackingWidth)
+	assert.Equal(t, 3850, defendingWidth)
+	for _, target := range attacking {
+		assert.Equal(t, DefaultShape.RegionRadius, target.Radius)
+	}
+}
+
+func TestFormation_Targets_FollowTheBall(t *testing.T) {
+	field := arena.NewField()
+	back := FourFourTwo.Targets(field, arena.HomeTeam, physics.Point{PosX: 3000, PosY: 2000}, false)
+	front := FourFourTwo.Targets(field, arena.HomeTeam, physics.Point{PosX: 15000, PosY: 2000}, false)
+	assert.True(t, meanX(back) < meanX(front))
+
+	// the block moves towards the ball side
+	assert.True(t, float64(back["7"].Center.PosY) < FourFourTwo.Positions["7"].Y*units.FieldHeight)
+
+	// the goalkeeper follows the ball between the poles
+	assert.Equal(t, physics.Point{PosX: 600, PosY: 3500}, back["1"].Center)
+	top := FourFourTwo.Targets(field, arena.HomeTeam, physics.Point{PosX: 3000, PosY: 6000}, false)
+	assert.Equal(t, physics.Point{PosX: 600, PosY: 5500}, top["1"].Center)
+}
+
+func TestFormation_Targets_GoalZone(t *testing.T) {
+	field := arena.NewField()
+	targets := FourThreeThree.Targets(field, arena.HomeTeam, physics.Point{PosX: units.FieldWidth - 100, PosY: units.FieldHeight / 2}, true)
+	mouth := field.GoalMouth(arena.AwayTeam)
+	for number, target := range targets {
+		assert.True(t, field.Contains(target.Center), number)
+		distance := target.Center.DistanceToSegment(mouth.A, mouth.B)
+		assert.True(t, distance >= float64(units.GoalZoneRange+units.PlayerSize/2), "player %s is at %f from the goal", number, distance)
+	}
+}
+
+func TestFormation_Targets_AwayTeam(t *testing.T) {
+	field := arena.NewField()
+	ball := physics.Point{PosX: 7000, PosY: 3000}
+	home := ThreeFiveTwo.Targets(field, arena.HomeTeam, ball, true)
+	away := ThreeFiveTwo.Targets(field, arena.AwayTeam, arena.Mirror(arena.AwayTeam).Point(ball), true)
+	for number, target := range home {
+		assert.Equal(t, arena.Mirror(arena.AwayTeam).Point(target.Center), away[number].Center, number)
+	}
+}
+
+func TestShape_Targets(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.PlayerSize = 1000
+	field := arena.NewFieldFromRules(rules)
+	shape := DefaultShape
+	shape.GoalkeeperAttraction = 0
+
+	// the goalkeeper stays on the goal center
+	ball := physics.Point{PosX: 3000, PosY: 2000}
+	targets := shape.Targets(FourFourTwo, field, arena.HomeTeam, ball, false)
+	assert.Equal(t, physics.Point{PosX: 600, PosY: units.FieldHeight / 2}, targets["1"].Center)
+
+	// the bodies of the field player size do not touch the opponent goal zone
+	targets = shape.Targets(FourThreeThree, field, arena.HomeTeam, physics.Point{PosX: units.FieldWidth - 100, PosY: units.FieldHeight / 2}, true)
+	mouth := field.GoalMouth(arena.AwayTeam)
+	for number, target := range targets {
+		distance := target.Center.DistanceToSegment(mouth.A, mouth.B)
+		assert.True(t, distance >= float64(units.GoalZoneRange+rules.PlayerSize/2), "player %s is at %f from the goal", number, distance)
+	}
+}
@@ -0,0 +1,78 @@
+// Package formations defines the players positions of a team. The positions are team relative, so the same formation
+// is used by both teams.
+package formations
+
+import (
+	"fmt"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/physics"
+	"math"
+	"sort"
+)
+
+// Position is a team relative position, normalized to the field size. X is 0 at the team own goal line and 1 at
+// the opponent goal line, and Y is 0 at the bottom border and 1 at the top border, as seen by the team attacking
+// towards +X (see arena.Mirror).
+type Position struct {
+	X float64 `json:"x" yaml:"x"`
+	Y float64 `json:"y" yaml:"y"`
+}
+
+// Formation maps each player number to its kickoff position
+type Formation struct {
+	// Name identifies the formation (e.g. 4-4-2)
+	Name string `json:"name" yaml:"name"`
+	// Positions are the kickoff positions of each player
+	Positions map[arena.PlayerNumber]Position `json:"positions" yaml:"positions"`
+}
+
+// Numbers lists the player numbers of the formation ordered numerically
+func (f Formation) Numbers() []arena.PlayerNumber {
+	numbers := make([]arena.PlayerNumber, 0, len(f.Positions))
+	for number := range f.Positions {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool {
+		if len(numbers[i]) != len(numbers[j]) {
+			return len(numbers[i]) < len(numbers[j])
+		}
+		return numbers[i] < numbers[j]
+	})
+	return numbers
+}
+
+// Point finds the absolute position of the player of the team in the field
+func (f Formation) Point(field arena.Field, place arena.TeamPlace, number arena.PlayerNumber) (physics.Point, error) {
+	position, ok := f.Positions[number]
+	if !ok {
+		return physics.Point{}, fmt.Errorf("the formation %s has no player number %s", f.Name, number)
+	}
+	return arena.Mirror(place).PointIn(field, toPoint(field, position)), nil
+}
+
+// InitialCoords finds the absolute kickoff position of each player of the team
+func (f Formation) InitialCoords(field arena.Field, place arena.TeamPlace) map[arena.PlayerNumber]physics.Point {
+	coords := make(map[arena.PlayerNumber]physics.Point, len(f.Positions))
+	for number, position := range f.Positions {
+		coords[number] = arena.Mirror(place).PointIn(field, toPoint(field, position))
+	}
+	return coords
+}
+
+// Apply fills the player specifications initial coords with the player position in the formation
+func (f Formation) Apply(field arena.Field, place arena.TeamPlace, specs *arena.PlayerSpecifications) error {
+	point, err := f.Point(field, place, specs.Number)
+	if err != nil {
+		return err
+	}
+	specs.InitialCoords = point
+	return nil
+}
+
+// toPoint converts a normalized position to the home team frame
+func toPoint(field arena.Field, position Position) physics.Point {
+	return physics.Point{
+		PosX: int(math.Round(position.X * float64(field.Width))),
+		PosY: int(math.Round(position.Y * float64(field.Height))),
+	}
+}
@@ -0,0 +1,141 @@
+package formations
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestNamed(t *testing.T) {
+	assert.Equal(t, []string{"3-5-2", "4-3-3", "4-4-2"}, Names())
+	for _, name := range Names() {
+		formation, ok := Named(name)
+		assert.True(t, ok)
+		assert.Nil(t, formation.Validate(units.DefaultRules()), name)
+	}
+
+	formation, _ := Named("4-4-2")
+	formation.Positions["1"] = Position{X: 0.1, Y: 0.1}
+	assert.Equal(t, Position{X: 0.03, Y: 0.5}, FourFourTwo.Positions["1"], "the named formations must not be changed")
+
+	_, ok := Named("2-3-5")
+	assert.False(t, ok)
+}
+
+func TestFormation_Numbers(t *testing.T) {
+	assert.Equal(t, []arena.PlayerNumber{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}, FourFourTwo.Numbers())
+}
+
+func TestFormation_InitialCoords(t *testing.T) {
+	field := arena.NewField()
+	home := FourFourTwo.InitialCoords(field, arena.HomeTeam)
+	away := FourFourTwo.InitialCoords(field, arena.AwayTeam)
+	assert.Len(t, home, 11)
+	assert.Equal(t, physics.Point{PosX: 600, PosY: 5000}, home["1"])
+	assert.Equal(t, physics.Point{PosX: 19400, PosY: 5000}, away["1"])
+	assert.Equal(t, physics.Point{PosX: 4000, PosY: 1500}, home["2"])
+	assert.Equal(t, physics.Point{PosX: 16000, PosY: 8500}, away["2"])
+	for number, p := range away {
+		assert.Equal(t, arena.Mirror(arena.AwayTeam).Point(home[number]), p)
+		assert.True(t, field.Half(arena.AwayTeam).Contains(p))
+	}
+
+	rules := units.DefaultRules()
+	rules.FieldWidth = 30000
+	p, err := FourFourTwo.Point(arena.NewFieldFromRules(rules), arena.AwayTeam, "10")
+	assert.Nil(t, err)
+	assert.Equal(t, physics.Point{PosX: 16500, PosY: 6000}, p)
+
+	_, err = FourFourTwo.Point(field, arena.HomeTeam, "12")
+	assert.NotNil(t, err)
+}
+
+func TestFormation_Apply(t *testing.T) {
+	specs := arena.PlayerSpecifications{Number: "7"}
+	assert.Nil(t, ThreeFiveTwo.Apply(arena.NewField(), arena.AwayTeam, &specs))
+	assert.Equal(t, physics.Point{PosX: 14600, PosY: 5000}, specs.InitialCoords)
+
+	specs = arena.PlayerSpecifications{Number: "13"}
+	assert.NotNil(t, ThreeFiveTwo.Apply(arena.NewField(), arena.AwayTeam, &specs))
+}
+
+func TestFormation_Validate(t *testing.T) {
+	rules := units.DefaultRules()
+	cases := map[string]struct {
+		number   arena.PlayerNumber
+		position *Position
+		err      string
+	}{
+		"missing":  {number: "5", err: "invalid formation 4-4-2: the player 5 is missing"},
+		"outside":  {number: "5", position: &Position{X: 0.2, Y: 1.1}, err: "invalid formation 4-4-2: player 5 is out of the field"},
+		"border":   {number: "5", position: &Position{X: 0.2, Y: 0.01}, err: "invalid formation 4-4-2: player 5 touches the field borders"},
+		"half":     {number: "10", position: &Position{X: 0.6, Y: 0.3}, err: "invalid formation 4-4-2: player 10 is not in the team own half"},
+		"touching": {number: "11", position: &Position{X: 0.45, Y: 0.42}, err: "invalid formation 4-4-2: player 10 touches the player 11"},
+	}
+	for name, c := range cases {
+		formation := FourFourTwo.Copy()
+		if c.position == nil {
+			delete(formation.Positions, c.number)
+		} else {
+			formation.Positions[c.number] = *c.position
+		}
+		err := formation.Validate(rules)
+		assert.EqualError(t, err, c.err, name)
+		assert.IsType(t, &ValidationError{}, err)
+	}
+
+	formation := FourFourTwo.Copy()
+	formation.Positions["12"] = Position{X: 0.1, Y: 0.1}
+	assert.EqualError(t, formation.Validate(rules), "invalid formation 4-4-2: it must have 11 players, not 12")
+
+	rules.FieldNeutralCenter = 1000
+	assert.Nil(t, FourFourTwo.Validate(rules))
+	formation = FourFourTwo.Copy()
+	formation.Positions["10"] = Position{X: 0.47, Y: 0.5}
+	assert.EqualError(t, formation.Validate(rules), "invalid formation 4-4-2: player 10 is in the neutral center")
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "formations")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	yamlFile := filepath.Join(dir, "compact.yaml")
+	content := "positions:\n"
+	for _, number := range FourFourTwo.Numbers() {
+		p := FourFourTwo.Positions[number]
+		content += "  " + string(number) + ": {x: " + formatFloat(p.X*0.9) + ", y: " + formatFloat(p.Y) + "}\n"
+	}
+	assert.Nil(t, ioutil.WriteFile(yamlFile, []byte(content), 0644))
+	formation, err := Load(yamlFile, units.DefaultRules())
+	assert.Nil(t, err)
+	assert.Equal(t, "compact", formation.Name)
+	assert.Len(t, formation.Positions, 11)
+	assert.InDelta(t, 0.405, formation.Positions["10"].X, 1e-9)
+
+	jsonFile := filepath.Join(dir, "broken.json")
+	assert.Nil(t, ioutil.WriteFile(jsonFile, []byte(`{"name": "broken", "positions": {"1": {"x": 0.03, "y": 0.5}}}`), 0644))
+	_, err = Load(jsonFile, units.DefaultRules())
+	assert.EqualError(t, err, "invalid formation broken: the player 2 is missing")
+
+	_, err = Load(filepath.Join(dir, "missing.json"), units.DefaultRules())
+	assert.NotNil(t, err)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func TestParseJSON_UnknownKey(t *testing.T) {
+	_, err := ParseJSON([]byte(`{"name": "typo", "position": {"1": {"x": 0.03, "y": 0.5}}}`), units.DefaultRules())
+	assert.EqualError(t, err, `invalid formation: json: unknown field "position"`)
+
+	_, err = ParseJSON([]byte(`{"name": "typo", "positions": {"1": {"x": 0.03, "y": 0.5, "z": 1}}}`), units.DefaultRules())
+	assert.EqualError(t, err, `invalid formation: json: unknown field "z"`)
+}
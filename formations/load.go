@@ -0,0 +1,63 @@
+package formations
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/lugobots/arena/units"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// ParseJSON reads a formation from JSON and validates it against the rules. Unknown keys are not accepted, as in
+// ParseYAML.
+func ParseJSON(data []byte, rules units.Rules) (Formation, error) {
+	var formation Formation
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&formation); err != nil {
+		return Formation{}, fmt.Errorf("invalid formation: %s", err)
+	}
+	if err := formation.Validate(rules); err != nil {
+		return Formation{}, err
+	}
+	return formation, nil
+}
+
+// ParseYAML reads a formation from YAML and validates it against the rules
+func ParseYAML(data []byte, rules units.Rules) (Formation, error) {
+	var formation Formation
+	if err := yaml.UnmarshalStrict(data, &formation); err != nil {
+		return Formation{}, fmt.Errorf("invalid formation: %s", err)
+	}
+	if err := formation.Validate(rules); err != nil {
+		return Formation{}, err
+	}
+	return formation, nil
+}
+
+// Load reads a formation from a file and validates it against the rules. Files with the extension .yaml or .yml are
+// read as YAML, any other extension is read as JSON. The file name is used as the formation name when the file
+// does not define one.
+func Load(path string, rules units.Rules) (Formation, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Formation{}, err
+	}
+	var formation Formation
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		formation, err = ParseYAML(data, rules)
+	default:
+		formation, err = ParseJSON(data, rules)
+	}
+	if err != nil {
+		return Formation{}, err
+	}
+	if formation.Name == "" {
+		formation.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return formation, nil
+}
@@ -0,0 +1,94 @@
+package formations
+
+import (
+	"github.com/lugobots/arena"
+	"sort"
+)
+
+// FourFourTwo has four defenders, four midfielders and two forwards
+var FourFourTwo = Formation{
+	Name: "4-4-2",
+	Positions: map[arena.PlayerNumber]Position{
+		"1":  {X: 0.03, Y: 0.5},
+		"2":  {X: 0.2, Y: 0.15},
+		"3":  {X: 0.18, Y: 0.38},
+		"4":  {X: 0.18, Y: 0.62},
+		"5":  {X: 0.2, Y: 0.85},
+		"6":  {X: 0.33, Y: 0.15},
+		"7":  {X: 0.3, Y: 0.38},
+		"8":  {X: 0.3, Y: 0.62},
+		"9":  {X: 0.33, Y: 0.85},
+		"10": {X: 0.45, Y: 0.4},
+		"11": {X: 0.45, Y: 0.6},
+	},
+}
+
+// FourThreeThree has four defenders, three midfielders and three forwards
+var FourThreeThree = Formation{
+	Name: "4-3-3",
+	Positions: map[arena.PlayerNumber]Position{
+		"1":  {X: 0.03, Y: 0.5},
+		"2":  {X: 0.2, Y: 0.15},
+		"3":  {X: 0.18, Y: 0.38},
+		"4":  {X: 0.18, Y: 0.62},
+		"5":  {X: 0.2, Y: 0.85},
+		"6":  {X: 0.3, Y: 0.3},
+		"7":  {X: 0.28, Y: 0.5},
+		"8":  {X: 0.3, Y: 0.7},
+		"9":  {X: 0.44, Y: 0.15},
+		"10": {X: 0.46, Y: 0.5},
+		"11": {X: 0.44, Y: 0.85},
+	},
+}
+
+// ThreeFiveTwo has three defenders, five midfielders and two forwards
+var ThreeFiveTwo = Formation{
+	Name: "3-5-2",
+	Positions: map[arena.PlayerNumber]Position{
+		"1":  {X: 0.03, Y: 0.5},
+		"2":  {X: 0.18, Y: 0.25},
+		"3":  {X: 0.16, Y: 0.5},
+		"4":  {X: 0.18, Y: 0.75},
+		"5":  {X: 0.32, Y: 0.08},
+		"6":  {X: 0.3, Y: 0.3},
+		"7":  {X: 0.27, Y: 0.5},
+		"8":  {X: 0.3, Y: 0.7},
+		"9":  {X: 0.32, Y: 0.92},
+		"10": {X: 0.45, Y: 0.4},
+		"11": {X: 0.45, Y: 0.6},
+	},
+}
+
+var named = map[string]Formation{
+	FourFourTwo.Name:    FourFourTwo,
+	FourThreeThree.Name: FourThreeThree,
+	ThreeFiveTwo.Name:   ThreeFiveTwo,
+}
+
+// Named returns a copy of the formation with the name. The second value is false when the name is unknown.
+func Named(name string) (Formation, bool) {
+	formation, ok := named[name]
+	if !ok {
+		return Formation{}, false
+	}
+	return formation.Copy(), true
+}
+
+// Names lists the names of the available formations in alphabetical order
+func Names() []string {
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Copy returns a copy of the formation that may be changed without affecting the original one
+func (f Formation) Copy() Formation {
+	copied := Formation{Name: f.Name, Positions: make(map[arena.PlayerNumber]Position, len(f.Positions))}
+	for number, position := range f.Positions {
+		copied.Positions[number] = position
+	}
+	return copied
+}
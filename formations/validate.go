@@ -0,0 +1,69 @@
+package formations
+
+import (
+	"fmt"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/units"
+)
+
+// TeamSize is the number of players of a complete formation
+const TeamSize = arena.MaxPlayerNumber
+
+// ValidationError describes why a formation cannot be used
+type ValidationError struct {
+	// Formation is the name of the invalid formation
+	Formation string
+	// Number is the player whose position is invalid, it is empty when the problem is not related to a player
+	Number arena.PlayerNumber
+	// Reason describes the problem
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Number == "" {
+		return fmt.Sprintf("invalid formation %s: %s", e.Formation, e.Reason)
+	}
+	return fmt.Sprintf("invalid formation %s: player %s %s", e.Formation, e.Number, e.Reason)
+}
+
+// Validate checks if the formation may be used at the kickoff of a game with the rules: it must have all players,
+// and each player body must be inside the team own half and out of the neutral center circle, without touching the
+// other players. The own half is never in the opponent goal zone, since the rules do not let the goal zones overlap.
+func (f Formation) Validate(rules units.Rules) error {
+	invalid := func(number arena.PlayerNumber, reason string, args ...interface{}) error {
+		return &ValidationError{Formation: f.Name, Number: number, Reason: fmt.Sprintf(reason, args...)}
+	}
+	for _, number := range arena.PlayerNumbers() {
+		if _, ok := f.Positions[number]; !ok {
+			return invalid("", "the player %s is missing", number)
+		}
+	}
+	if len(f.Positions) != TeamSize {
+		return invalid("", "it must have %d players, not %d", TeamSize, len(f.Positions))
+	}
+
+	field := arena.NewFieldFromRules(rules)
+	radius := rules.PlayerSize / 2
+	numbers := f.Numbers()
+	for i, number := range numbers {
+		position := f.Positions[number]
+		if position.X < 0 || position.X > 1 || position.Y < 0 || position.Y > 1 {
+			return invalid(number, "is out of the field")
+		}
+		p := toPoint(field, position)
+		switch {
+		case p.PosX < radius || p.PosY < radius || p.PosY > field.Height-radius:
+			return invalid(number, "touches the field borders")
+		case p.PosX+radius > field.Center.PosX:
+			return invalid(number, "is not in the team own half")
+		case field.NeutralCenter().Contains(p):
+			return invalid(number, "is in the neutral center")
+		}
+		for _, other := range numbers[i+1:] {
+			if p.DistanceTo(toPoint(field, f.Positions[other])) < float64(rules.PlayerSize) {
+				return invalid(number, "touches the player %s", other)
+			}
+		}
+	}
+	return nil
+}
@@ -10,4 +10,5 @@ require (
 	github.com/pkg/errors v0.8.1
 	github.com/sirupsen/logrus v1.4.0
 	github.com/stretchr/testify v1.3.0
+	gopkg.in/yaml.v2 v2.2.2
 )
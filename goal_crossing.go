@@ -0,0 +1,59 @@
+package arena
+
+import (
+	"github.com/lugobots/arena/physics"
+)
+
+// GoalCrossing describes how the ball crossed the line of a goal
+type GoalCrossing struct {
+	// Goal is the goal whose line was crossed
+	Goal Goal
+	// Point is where the ball center crossed the goal line
+	Point physics.Point
+	// Scored is true when the ball center crossed the goal line between the poles (poles included)
+	Scored bool
+	// HitPost is true when the ball crossed the line out of the goal mouth, but close enough to touch one of the poles
+	HitPost bool
+}
+
+// CheckGoalCrossing checks if the ball moving from `from` to `to` crossed the line of the goal, coming from inside
+// the field. The second returned value is false when the ball did not cross the goal line at all.
+// The ball is considered inside the goal when its center crosses the line between the poles, so a ball that
+// touches the pole on its way to the goal still scores. A ball crossing the line out of the goal mouth, but at a
+// distance to the pole smaller than its radius, hits the post and does not score.
+func CheckGoalCrossing(from, to physics.Point, ballSize int, goal Goal) (GoalCrossing, bool) {
+	lineX := goal.Center.PosX
+	inward := 1
+	if lineX > FieldCenter.PosX {
+		inward = -1
+	}
+	// the ball must leave the field side of the line and reach or pass it
+	if (from.PosX-lineX)*inward <= 0 || (to.PosX-lineX)*inward > 0 {
+		return GoalCrossing{}, false
+	}
+	crossPoint, _, err := physics.Determinant(from, to, goal.BottomPole, goal.TopPole)
+	if err != nil {
+		return GoalCrossing{}, false
+	}
+	crossPoint.PosX = lineX
+
+	crossing := GoalCrossing{Goal: goal, Point: crossPoint}
+	if crossPoint.PosY >= goal.BottomPole.PosY && crossPoint.PosY <= goal.TopPole.PosY {
+		crossing.Scored = true
+	} else {
+		radius := float64(ballSize) / 2
+		crossing.HitPost = crossPoint.DistanceTo(goal.BottomPole) <= radius || crossPoint.DistanceTo(goal.TopPole) <= radius
+	}
+	return crossing, true
+}
+
+// FindGoalCrossing checks the ball movement against both goals. The second returned value is false when the ball
+// did not cross any goal line.
+func FindGoalCrossing(from, to physics.Point, ballSize int) (GoalCrossing, bool) {
+	for _, goal := range []Goal{HomeTeamGoal, AwayTeamGoal} {
+		if crossing, ok := CheckGoalCrossing(from, to, ballSize, goal); ok {
+			return crossing, true
+		}
+	}
+	return GoalCrossing{}, false
+}
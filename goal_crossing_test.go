@@ -0,0 +1,74 @@
+package arena
+
+import (
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheckGoalCrossing(t *testing.T) {
+	midY := units.FieldHeight / 2
+	table := map[string]struct {
+		from     physics.Point
+		to       physics.Point
+		goal     Goal
+		crossed  bool
+		scored   bool
+		hitPost  bool
+		crossing physics.Point
+	}{
+		"straight shot home goal": {
+			physics.Point{PosX: 300, PosY: midY}, physics.Point{PosX: -100, PosY: midY}, HomeTeamGoal,
+			true, true, false, physics.Point{PosX: 0, PosY: midY},
+		},
+		"ball stops on the line": {
+			physics.Point{PosX: 300, PosY: midY}, physics.Point{PosX: 0, PosY: midY}, HomeTeamGoal,
+			true, true, false, physics.Point{PosX: 0, PosY: midY},
+		},
+		"ball does not reach the line": {
+			physics.Point{PosX: 300, PosY: midY}, physics.Point{PosX: 1, PosY: midY}, HomeTeamGoal,
+			false, false, false, physics.Point{},
+		},
+		"ball leaving the goal": {
+			physics.Point{PosX: -100, PosY: midY}, physics.Point{PosX: 300, PosY: midY}, HomeTeamGoal,
+			false, false, false, physics.Point{},
+		},
+		"diagonal shot away goal": {
+			physics.Point{PosX: units.FieldWidth - 200, PosY: midY - 200}, physics.Point{PosX: units.FieldWidth + 200, PosY: midY + 200}, AwayTeamGoal,
+			true, true, false, physics.Point{PosX: units.FieldWidth, PosY: midY},
+		},
+		"exactly on the top pole": {
+			physics.Point{PosX: 100, PosY: units.GoalMaxY}, physics.Point{PosX: -100, PosY: units.GoalMaxY}, HomeTeamGoal,
+			true, true, false, physics.Point{PosX: 0, PosY: units.GoalMaxY},
+		},
+		"hits the bottom post from outside": {
+			physics.Point{PosX: 100, PosY: units.GoalMinY - 50}, physics.Point{PosX: -100, PosY: units.GoalMinY - 50}, HomeTeamGoal,
+			true, false, true, physics.Point{PosX: 0, PosY: units.GoalMinY - 50},
+		},
+		"wide shot": {
+			physics.Point{PosX: 100, PosY: units.GoalMinY - 500}, physics.Point{PosX: -100, PosY: units.GoalMinY - 500}, HomeTeamGoal,
+			true, false, false, physics.Point{PosX: 0, PosY: units.GoalMinY - 500},
+		},
+	}
+	for title, set := range table {
+		crossing, crossed := CheckGoalCrossing(set.from, set.to, units.BallSize, set.goal)
+		assert.Equal(t, set.crossed, crossed, title)
+		assert.Equal(t, set.scored, crossing.Scored, title)
+		assert.Equal(t, set.hitPost, crossing.HitPost, title)
+		if set.crossed {
+			assert.Equal(t, set.crossing, crossing.Point, title)
+			assert.Equal(t, set.goal, crossing.Goal, title)
+		}
+	}
+}
+
+func TestFindGoalCrossing(t *testing.T) {
+	crossing, crossed := FindGoalCrossing(physics.Point{PosX: units.FieldWidth - 10, PosY: 5000}, physics.Point{PosX: units.FieldWidth + 10, PosY: 5000}, units.BallSize)
+	assert.True(t, crossed)
+	assert.True(t, crossing.Scored)
+	assert.Equal(t, AwayTeam, crossing.Goal.Place)
+
+	_, crossed = FindGoalCrossing(FieldCenter, physics.Point{PosX: 5000, PosY: 5000}, units.BallSize)
+	assert.False(t, crossed)
+}
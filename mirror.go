@@ -0,0 +1,103 @@
+package arena
+
+import (
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+)
+
+// Mirror transforms values between the absolute frame used by the game server and the frame of a team, where the
+// team always attacks towards +X (as the home team does).
+// The home team frame is the absolute frame, so Mirror(HomeTeam) does not change any value. The away team frame is
+// rotated 180 degrees around the field center. Since the transformation is its own inverse, the same Mirror converts
+// absolute values to team relative values and back, e.g. Mirror(AwayTeam).Point(Mirror(AwayTeam).Point(p)) == p.
+type Mirror TeamPlace
+
+// IsIdentity returns true when the mirror does not change the values
+func (m Mirror) IsIdentity() bool {
+	return TeamPlace(m) != AwayTeam
+}
+
+// Point transforms a point
+func (m Mirror) Point(p physics.Point) physics.Point {
+	if m.IsIdentity() {
+		return p
+	}
+	return physics.Point{
+		PosX: units.FieldWidth - p.PosX,
+		PosY: units.FieldHeight - p.PosY,
+	}
+}
+
+// PointIn transforms a point of a field whose size is not the one defined by the units constants
+func (m Mirror) PointIn(f Field, p physics.Point) physics.Point {
+	if m.IsIdentity() {
+		return p
+	}
+	return physics.Point{
+		PosX: f.Width - p.PosX,
+		PosY: f.Height - p.PosY,
+	}
+}
+
+// Vector returns a transformed copy of the vector
+func (m Mirror) Vector(v *physics.Vector) *physics.Vector {
+	copied := v.Copy()
+	if m.IsIdentity() {
+		return copied
+	}
+	return copied.Invert()
+}
+
+// Velocity returns a transformed copy of the velocity. The speed is not affected.
+func (m Mirror) Velocity(v physics.Velocity) physics.Velocity {
+	if v.Direction == nil {
+		return v
+	}
+	copied := v.Copy()
+	copied.Direction = m.Vector(v.Direction)
+	return copied
+}
+
+// Goal transforms the goal coordinates. The poles are swapped, so the top pole still has the higher Y coordinate.
+// The goal place is not affected, it still identifies the team that defends the goal.
+func (m Mirror) Goal(g Goal) Goal {
+	if m.IsIdentity() {
+		return g
+	}
+	return Goal{
+		Center:     m.Point(g.Center),
+		Place:      g.Place,
+		TopPole:    m.Point(g.BottomPole),
+		BottomPole: m.Point(g.TopPole),
+	}
+}
+
+// Element returns a transformed copy of the element
+func (m Mirror) Element(e physics.Element) physics.Element {
+	e.Coords = m.Point(e.Coords)
+	e.Velocity = m.Velocity(e.Velocity)
+	return e
+}
+
+// Player returns a transformed copy of the player
+func (m Mirror) Player(p Player) Player {
+	p.Element = m.Element(p.Element)
+	return p
+}
+
+// Snapshot returns a transformed copy of the snapshot. Only the elements coordinates and velocities are changed,
+// the teams keep their places.
+func (m Mirror) Snapshot(s Snapshot) Snapshot {
+	copied := s.Copy()
+	copied.Ball.Element = m.Element(copied.Ball.Element)
+	if copied.Ball.Holder != nil {
+		holder := m.Player(*copied.Ball.Holder)
+		copied.Ball.Holder = &holder
+	}
+	for _, team := range []*Team{&copied.HomeTeam, &copied.AwayTeam} {
+		for i, player := range team.Players {
+			team.Players[i] = m.Player(player)
+		}
+	}
+	return copied
+}
@@ -0,0 +1,92 @@
+package arena
+
+import (
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMirror_Point(t *testing.T) {
+	p := physics.Point{PosX: 1000, PosY: 2500}
+
+	assert.Equal(t, p, Mirror(HomeTeam).Point(p))
+	assert.Equal(t, physics.Point{PosX: units.FieldWidth - 1000, PosY: units.FieldHeight - 2500}, Mirror(AwayTeam).Point(p))
+	assert.Equal(t, p, Mirror(AwayTeam).Point(Mirror(AwayTeam).Point(p)))
+	assert.Equal(t, FieldCenter, Mirror(AwayTeam).Point(FieldCenter))
+}
+
+func TestMirror_PointIn(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.FieldWidth = 30000
+	field := NewFieldFromRules(rules)
+	p := physics.Point{PosX: 1000, PosY: 2500}
+
+	assert.Equal(t, p, Mirror(HomeTeam).PointIn(field, p))
+	assert.Equal(t, physics.Point{PosX: 29000, PosY: units.FieldHeight - 2500}, Mirror(AwayTeam).PointIn(field, p))
+	assert.Equal(t, Mirror(AwayTeam).Point(p), Mirror(AwayTeam).PointIn(NewField(), p))
+}
+
+func TestMirror_Velocity(t *testing.T) {
+	velocity := physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: 3, PosY: 4}, 50)
+
+	mirrored := Mirror(AwayTeam).Velocity(velocity)
+	assert.Equal(t, velocity.Speed, mirrored.Speed)
+	assert.Equal(t, -velocity.Direction.GetX(), mirrored.Direction.GetX())
+	assert.Equal(t, -velocity.Direction.GetY(), mirrored.Direction.GetY())
+
+	back := Mirror(AwayTeam).Velocity(mirrored)
+	assert.Equal(t, velocity, back)
+
+	// the original value must not be changed
+	assert.Equal(t, 60.0, velocity.Direction.GetX())
+
+	from := physics.Point{PosX: 500, PosY: 500}
+	target := velocity.Target(from)
+	assert.Equal(t, Mirror(AwayTeam).Point(target), mirrored.Target(Mirror(AwayTeam).Point(from)))
+}
+
+func TestMirror_Goal(t *testing.T) {
+	assert.Equal(t, AwayTeamGoal, Mirror(HomeTeam).Goal(AwayTeamGoal))
+
+	mirrored := Mirror(AwayTeam).Goal(AwayTeamGoal)
+	assert.Equal(t, AwayTeam, mirrored.Place)
+	assert.Equal(t, HomeTeamGoal.Center, mirrored.Center)
+	assert.Equal(t, HomeTeamGoal.TopPole, mirrored.TopPole)
+	assert.Equal(t, HomeTeamGoal.BottomPole, mirrored.BottomPole)
+	assert.Equal(t, AwayTeamGoal, Mirror(AwayTeam).Goal(mirrored))
+}
+
+func TestMirror_Snapshot(t *testing.T) {
+	holder := Player{
+		Element:   physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 300, PosY: 400}, Velocity: physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, 20)},
+		Number:    "5",
+		TeamPlace: AwayTeam,
+	}
+	snapshot := Snapshot{
+		Turn:  10,
+		State: Listening,
+		Ball: Ball{
+			Element: physics.Element{Size: units.BallSize, Coords: physics.Point{PosX: 300, PosY: 400}, Velocity: physics.NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, 0)},
+			Holder:  &holder,
+		},
+		HomeTeam: Team{Place: HomeTeam, Players: []Player{
+			{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 1000, PosY: 900}}, Number: "1", TeamPlace: HomeTeam},
+		}},
+		AwayTeam: Team{Place: AwayTeam, Players: []Player{holder}},
+	}
+
+	mirrored := Mirror(AwayTeam).Snapshot(snapshot)
+	assert.Equal(t, physics.Point{PosX: units.FieldWidth - 300, PosY: units.FieldHeight - 400}, mirrored.Ball.Coords)
+	assert.Equal(t, mirrored.Ball.Coords, mirrored.Ball.Holder.Coords)
+	assert.Equal(t, physics.Point{PosX: units.FieldWidth - 1000, PosY: units.FieldHeight - 900}, mirrored.HomeTeam.Players[0].Coords)
+	assert.Equal(t, -100.0, mirrored.AwayTeam.Players[0].Velocity.Direction.GetX())
+	assert.Equal(t, AwayTeam, mirrored.AwayTeam.Place)
+
+	// the original snapshot is not affected
+	assert.Equal(t, physics.Point{PosX: 300, PosY: 400}, snapshot.Ball.Coords)
+	assert.Equal(t, 100.0, snapshot.AwayTeam.Players[0].Velocity.Direction.GetX())
+
+	assert.Equal(t, snapshot, Mirror(AwayTeam).Snapshot(mirrored))
+	assert.Equal(t, snapshot, Mirror(HomeTeam).Snapshot(snapshot))
+}
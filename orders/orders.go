@@ -3,6 +3,7 @@ package orders
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/lugobots/arena"
 	"github.com/lugobots/arena/physics"
 	"github.com/pkg/errors"
 )
@@ -28,6 +29,26 @@ type JumpOrderData struct {
 	Velocity physics.Velocity `json:"velocity"`
 }
 
+// Batch is the set of orders sent by a player in a turn
+type Batch struct {
+	// Place identifies the team of the player
+	Place arena.TeamPlace `json:"team_place"`
+	// Number identifies the player in its team
+	Number arena.PlayerNumber `json:"number"`
+	// Orders are the orders sent by the player
+	Orders []Order `json:"orders"`
+}
+
+// PlayerMessage is the message sent by the player to the game server
+type PlayerMessage struct {
+	// Type identifies the message type, it is ORDER when the player is sending orders
+	Type arena.MsgType `json:"type"`
+	// Orders are the orders for the current turn
+	Orders []Order `json:"orders"`
+	// Debug is an optional message to be shown by the web client
+	Debug string `json:"message"`
+}
+
 const (
 	// orders sent by the PLAYER
 
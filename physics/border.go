@@ -0,0 +1,165 @@
+package physics
+
+import "math"
+
+// Border identifies one of the field borders
+type Border string
+
+const (
+	// TopBorder is the border where Y is equal to the field height
+	TopBorder Border = "top"
+	// RightBorder is the border where X is equal to the field width
+	RightBorder Border = "right"
+	// BottomBorder is the border where Y is zero
+	BottomBorder Border = "bottom"
+	// LeftBorder is the border where X is zero
+	LeftBorder Border = "left"
+)
+
+// ClampToField returns the closest point to `p` where an element of size `size` stays entirely inside the field
+func ClampToField(p Point, size int) Point {
+	return defaultEngine.ClampToField(p, size)
+}
+
+// ClampToField returns the closest point to `p` where an element of size `size` stays entirely inside the field
+func (e Engine) ClampToField(p Point, size int) Point {
+	radius := size / 2
+	return Point{
+		PosX: clampInt(p.PosX, radius, e.Rules.FieldWidth-radius),
+		PosY: clampInt(p.PosY, radius, e.Rules.FieldHeight-radius),
+	}
+}
+
+// ClampPlayerTarget returns the point reached by a player moving from `from` with the velocity, limited to the
+// playable area. A player never leaves the field, so its body (PlayerSize) is kept inside the borders.
+func ClampPlayerTarget(from Point, velocity Velocity) Point {
+	return defaultEngine.ClampPlayerTarget(from, velocity)
+}
+
+// ClampPlayerTarget returns the point reached by a player moving from `from` with the velocity, limited to the
+// playable area
+func (e Engine) ClampPlayerTarget(from Point, velocity Velocity) Point {
+	return e.ClampToField(velocity.Target(from), e.Rules.PlayerSize)
+}
+
+// TouchedBorders lists the borders touched by an element of size `size` at the point `p`
+func TouchedBorders(p Point, size int) []Border {
+	return defaultEngine.TouchedBorders(p, size)
+}
+
+// TouchedBorders lists the borders touched by an element of size `size` at the point `p`
+func (e Engine) TouchedBorders(p Point, size int) []Border {
+	radius := size / 2
+	var borders []Border
+	if p.PosY+radius >= e.Rules.FieldHeight {
+		borders = append(borders, TopBorder)
+	}
+	if p.PosX+radius >= e.Rules.FieldWidth {
+		borders = append(borders, RightBorder)
+	}
+	if p.PosY-radius <= 0 {
+		borders = append(borders, BottomBorder)
+	}
+	if p.PosX-radius <= 0 {
+		borders = append(borders, LeftBorder)
+	}
+	return borders
+}
+
+// ReflectOnBorders finds the point reached by a ball of size `size` moving from `from` with the velocity. When the
+// ball would cross a border, the position and the direction are reflected as the ball bounced on that border.
+// The ball is not reflected by the left and right borders when it crosses them through the goal mouth.
+// It returns the final position, the resulting velocity (the original one is not changed), and the borders hit, in
+// the order they were hit.
+func ReflectOnBorders(from Point, velocity Velocity, size int) (Point, Velocity, []Border) {
+	return defaultEngine.ReflectOnBorders(from, velocity, size)
+}
+
+// ReflectOnBorders finds the point reached by a ball of size `size` moving from `from` with the velocity, reflecting
+// it on the field borders, see ReflectOnBorders
+func (e Engine) ReflectOnBorders(from Point, velocity Velocity, size int) (Point, Velocity, []Border) {
+	if velocity.Speed == 0 || velocity.Direction == nil {
+		return from, velocity, nil
+	}
+	result := velocity.Copy()
+	radius := float64(size) / 2
+	minX, maxX := radius, float64(e.Rules.FieldWidth)-radius
+	minY, maxY := radius, float64(e.Rules.FieldHeight)-radius
+
+	x, y := float64(from.PosX), float64(from.PosY)
+	dirX, dirY := velocity.Direction.Cos(), velocity.Direction.Sin()
+	// dx and dy are the movement left after each bounce
+	dx, dy := velocity.Speed*dirX, velocity.Speed*dirY
+
+	var borders []Border
+	throughGoal := false
+	// a ball may bounce on more than one border in the same turn (e.g. close to the corners), so the borders are
+	// reflected in the order the ball reaches them
+	for i := 0; i < 4; i++ {
+		hitX := hitTime(x, dx, minX, maxX)
+		if throughGoal {
+			hitX = math.Inf(1)
+		}
+		hitY := hitTime(y, dy, minY, maxY)
+		if hitX > 1 && hitY > 1 {
+			break
+		}
+		if hitX <= hitY {
+			if e.crossesGoalMouth(y + dy*hitX) {
+				// the ball leaves the field through the goal, so only the top and bottom borders are left
+				throughGoal = true
+				continue
+			}
+			x, y = x+dx*hitX, y+dy*hitX
+			dx, dy = -dx*(1-hitX), dy*(1-hitX)
+			dirX = -dirX
+			if dx < 0 {
+				borders = append(borders, RightBorder)
+			} else {
+				borders = append(borders, LeftBorder)
+			}
+			continue
+		}
+		x, y = x+dx*hitY, y+dy*hitY
+		dx, dy = dx*(1-hitY), -dy*(1-hitY)
+		dirY = -dirY
+		if dy < 0 {
+			borders = append(borders, TopBorder)
+		} else {
+			borders = append(borders, BottomBorder)
+		}
+	}
+	if len(borders) > 0 {
+		result.Direction.SetX(dirX)
+		result.Direction.SetY(dirY)
+		result.Direction.Normalize()
+	}
+	return Point{PosX: int(math.Round(x + dx)), PosY: int(math.Round(y + dy))}, result, borders
+}
+
+// hitTime finds the fraction of the movement `delta` from `position` when the ball reaches the limits. It is
+// greater than 1 when the movement ends before the limits.
+func hitTime(position, delta, min, max float64) float64 {
+	switch {
+	case delta > 0 && position+delta > max:
+		return math.Max(0, (max-position)/delta)
+	case delta < 0 && position+delta < min:
+		return math.Max(0, (min-position)/delta)
+	}
+	return math.Inf(1)
+}
+
+// crossesGoalMouth checks if the ball crossing the goal line at `crossY` passes between the goal poles
+func (e Engine) crossesGoalMouth(crossY float64) bool {
+	return crossY >= float64(e.Rules.GoalMinY()) && crossY <= float64(e.Rules.GoalMaxY())
+}
+
+func clampInt(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
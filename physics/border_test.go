@@ -0,0 +1,103 @@
+package physics
+
+import (
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestClampToField(t *testing.T) {
+	radius := units.PlayerSize / 2
+	assert.Equal(t, Point{PosX: radius, PosY: radius}, ClampToField(Point{PosX: -50, PosY: 0}, units.PlayerSize))
+	assert.Equal(t, Point{PosX: units.FieldWidth - radius, PosY: 500}, ClampToField(Point{PosX: units.FieldWidth + 10, PosY: 500}, units.PlayerSize))
+	assert.Equal(t, Point{PosX: 500, PosY: 500}, ClampToField(Point{PosX: 500, PosY: 500}, units.PlayerSize))
+}
+
+func TestClampPlayerTarget(t *testing.T) {
+	from := Point{PosX: 500, PosY: units.FieldHeight - 250}
+	target := ClampPlayerTarget(from, NewVelocityTo(Point{}, Point{PosY: 1}, units.PlayerMaxSpeed))
+	assert.Equal(t, Point{PosX: 500, PosY: units.FieldHeight - units.PlayerSize/2}, target)
+
+	target = ClampPlayerTarget(from, NewVelocityTo(Point{}, Point{PosX: 1}, units.PlayerMaxSpeed))
+	assert.Equal(t, Point{PosX: 600, PosY: units.FieldHeight - 250}, target)
+}
+
+func TestTouchedBorders(t *testing.T) {
+	assert.Equal(t, []Border{BottomBorder, LeftBorder}, TouchedBorders(Point{PosX: 100, PosY: 100}, units.BallSize))
+	assert.Equal(t, []Border{TopBorder, RightBorder}, TouchedBorders(Point{PosX: units.FieldWidth, PosY: units.FieldHeight}, units.BallSize))
+	assert.Nil(t, TouchedBorders(Point{PosX: 5000, PosY: 5000}, units.BallSize))
+}
+
+func TestReflectOnBorders(t *testing.T) {
+	radius := units.BallSize / 2
+	table := map[string]struct {
+		from      Point
+		velocity  Velocity
+		expected  Point
+		borders   []Border
+		expectedX float64
+		expectedY float64
+	}{
+		"no border": {
+			Point{PosX: 5000, PosY: 5000}, NewVelocityTo(Point{}, Point{PosX: 1}, 300),
+			Point{PosX: 5300, PosY: 5000}, nil, 100, 0,
+		},
+		"top border": {
+			Point{PosX: 5000, PosY: units.FieldHeight - radius - 100}, NewVelocityTo(Point{}, Point{PosY: 1}, 300),
+			Point{PosX: 5000, PosY: units.FieldHeight - radius - 200}, []Border{TopBorder}, 0, -100,
+		},
+		"left border out of the goal mouth": {
+			Point{PosX: radius + 100, PosY: 500}, NewVelocityTo(Point{}, Point{PosX: -1}, 300),
+			Point{PosX: radius + 200, PosY: 500}, []Border{LeftBorder}, 100, 0,
+		},
+		"left border through the goal mouth": {
+			Point{PosX: radius + 100, PosY: units.FieldHeight / 2}, NewVelocityTo(Point{}, Point{PosX: -1}, 300),
+			Point{PosX: radius - 200, PosY: units.FieldHeight / 2}, nil, -100, 0,
+		},
+		"corner reaching the right border first": {
+			Point{PosX: units.FieldWidth - radius - 50, PosY: radius + 100}, NewVelocityTo(Point{}, Point{PosX: 1, PosY: -1}, 282.842712474619),
+			Point{PosX: units.FieldWidth - radius - 150, PosY: radius + 100}, []Border{RightBorder, BottomBorder}, -70.71067811865476, 70.71067811865476,
+		},
+		"corner reaching the bottom border first": {
+			Point{PosX: units.FieldWidth - radius - 100, PosY: radius + 50}, NewVelocityTo(Point{}, Point{PosX: 1, PosY: -1}, 282.842712474619),
+			Point{PosX: units.FieldWidth - radius - 100, PosY: radius + 150}, []Border{BottomBorder, RightBorder}, -70.71067811865476, 70.71067811865476,
+		},
+	}
+	for title, set := range table {
+		originalX := set.velocity.Direction.GetX()
+		target, velocity, borders := ReflectOnBorders(set.from, set.velocity, units.BallSize)
+		assert.Equal(t, set.expected, target, title)
+		assert.Equal(t, set.borders, borders, title)
+		assert.InDelta(t, set.expectedX, velocity.Direction.GetX(), 0.0001, title)
+		assert.InDelta(t, set.expectedY, velocity.Direction.GetY(), 0.0001, title)
+		assert.Equal(t, set.velocity.Speed, velocity.Speed, title)
+		assert.Equal(t, originalX, set.velocity.Direction.GetX(), title)
+	}
+
+	still := NewZeroedVelocity(East)
+	target, _, borders := ReflectOnBorders(Point{PosX: 0, PosY: 0}, still, units.BallSize)
+	assert.Equal(t, Point{}, target)
+	assert.Nil(t, borders)
+}
+
+func TestEngine_ReflectOnBorders_GoalMouthAfterBounce(t *testing.T) {
+	radius := units.BallSize / 2
+	// the lower pole is between the point where the ball reaches the goal line after the bounce and the one found
+	// by a straight line from the start to the reflected target
+	rules := units.DefaultRules()
+	rules.GoalWidth = rules.FieldHeight - 2*(radius+150)
+	engine := NewEngine(rules)
+	maxX := rules.FieldWidth - radius
+
+	// the ball bounces on the bottom border and hits the goal line out of the goal mouth
+	from := Point{PosX: maxX - 100, PosY: radius + 100}
+	target, _, borders := engine.ReflectOnBorders(from, NewVelocityTo(Point{}, Point{PosX: 1, PosY: -2}, 447.21359549995793), units.BallSize)
+	assert.Equal(t, []Border{BottomBorder, RightBorder}, borders)
+	assert.Equal(t, Point{PosX: maxX - 100, PosY: radius + 300}, target)
+
+	// the same movement higher in the field crosses the goal mouth
+	from = Point{PosX: maxX - 100, PosY: rules.FieldHeight / 2}
+	target, _, borders = engine.ReflectOnBorders(from, NewVelocityTo(Point{}, Point{PosX: 1, PosY: -2}, 447.21359549995793), units.BallSize)
+	assert.Nil(t, borders)
+	assert.Equal(t, Point{PosX: maxX + 100, PosY: rules.FieldHeight/2 - 400}, target)
+}
@@ -0,0 +1,17 @@
+package physics
+
+import "github.com/lugobots/arena/units"
+
+// Engine applies the physics helpers using a rule set. The package functions (e.g. ReflectOnBorders, BallTrajectory)
+// use the default rules, an Engine allows using the same helpers with other field sizes and speeds.
+type Engine struct {
+	Rules units.Rules
+}
+
+// NewEngine creates an engine that applies the rules
+func NewEngine(rules units.Rules) Engine {
+	return Engine{Rules: rules}
+}
+
+// defaultEngine applies the rules defined by the units constants
+var defaultEngine = NewEngine(units.DefaultRules())
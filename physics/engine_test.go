@@ -0,0 +1,67 @@
+package physics
+
+import (
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newBiggerFieldRules() units.Rules {
+	rules := units.DefaultRules()
+	rules.FieldWidth = 30000
+	rules.FieldHeight = 16000
+	rules.GoalWidth = 4000
+	rules.BallDeceleration = 20
+	return rules
+}
+
+func TestEngine_DefaultRules(t *testing.T) {
+	engine := NewEngine(units.DefaultRules())
+	velocity := NewVelocityTo(Point{}, Point{PosX: 1, PosY: 1}, 300)
+	from := Point{PosX: 19800, PosY: 9800}
+
+	p1, v1, b1 := engine.ReflectOnBorders(from, velocity, units.BallSize)
+	p2, v2, b2 := ReflectOnBorders(from, velocity, units.BallSize)
+	assert.Equal(t, p2, p1)
+	assert.Equal(t, v2, v1)
+	assert.Equal(t, b2, b1)
+	assert.Equal(t, BallTrajectory(from, velocity, 0), engine.BallTrajectory(from, velocity, 0))
+}
+
+func TestEngine_BiggerField(t *testing.T) {
+	engine := NewEngine(newBiggerFieldRules())
+
+	assert.Equal(t, Point{PosX: 29800, PosY: 15800}, engine.ClampToField(Point{PosX: 40000, PosY: 20000}, 400))
+	assert.Equal(t, Point{PosX: 19800, PosY: 9800}, ClampToField(Point{PosX: 40000, PosY: 20000}, 400))
+	assert.Equal(t, []Border{RightBorder}, engine.TouchedBorders(Point{PosX: 29900, PosY: 8000}, units.BallSize))
+	assert.Empty(t, engine.TouchedBorders(Point{PosX: 19900, PosY: 8000}, units.BallSize))
+
+	// the goal mouth is between 6000 and 10000, so the ball is not reflected at Y 9000
+	p, _, borders := engine.ReflectOnBorders(Point{PosX: 29800, PosY: 9000}, NewVelocityTo(Point{}, Point{PosX: 1}, 300), units.BallSize)
+	assert.Empty(t, borders)
+	assert.Equal(t, Point{PosX: 30100, PosY: 9000}, p)
+	_, _, borders = ReflectOnBorders(Point{PosX: 19800, PosY: 9000}, NewVelocityTo(Point{}, Point{PosX: 1}, 300), units.BallSize)
+	assert.Equal(t, []Border{RightBorder}, borders)
+
+	assert.Equal(t, 400.0+380+360+340+320+300+280+260+240+220+200+180+160+140+120+100+80+60+40+20, engine.BallTravelDistance(400))
+	assert.Len(t, engine.BallTrajectory(Point{}, NewVelocityTo(Point{}, Point{PosX: 1}, 400), 0), 20)
+}
+
+func TestNewPathPlannerFromRules(t *testing.T) {
+	planner := NewPathPlannerFromRules(newBiggerFieldRules())
+	path, err := planner.Plan(Point{PosX: 25000, PosY: 12000}, Point{PosX: 29000, PosY: 15000}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []Point{{PosX: 29000, PosY: 15000}}, path)
+
+	path, err = NewPathPlanner().Plan(Point{PosX: 15000, PosY: 8000}, Point{PosX: 29000, PosY: 15000}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []Point{{PosX: 19800, PosY: 9800}}, path)
+}
+
+func TestNewGridFromRules(t *testing.T) {
+	grid := NewGridFromRules(newBiggerFieldRules(), 0)
+	far := &Element{Coords: Point{PosX: 28000, PosY: 15000}}
+	grid.Insert(far)
+	assert.Equal(t, []*Element{far}, grid.WithinRadius(Point{PosX: 28000, PosY: 15000}, 100, nil))
+	assert.Equal(t, []*Element{far}, grid.Nearest(Point{PosX: 27000, PosY: 14000}, 1, nil))
+}
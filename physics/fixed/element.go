@@ -0,0 +1,59 @@
+package fixed
+
+import (
+	"github.com/lugobots/arena/physics"
+)
+
+// Element is the deterministic version of physics.Element
+type Element struct {
+	Size     int
+	Coords   physics.Point
+	Velocity Velocity
+}
+
+// FromElement converts a physics element
+func FromElement(e physics.Element) Element {
+	return Element{Size: e.Size, Coords: e.Coords, Velocity: FromVelocity(e.Velocity)}
+}
+
+// Physics converts the element to a physics element
+func (e Element) Physics() physics.Element {
+	return physics.Element{Size: e.Size, Coords: e.Coords, Velocity: e.Velocity.Physics()}
+}
+
+// HasCollided detects if the element bodies are touching each other. The check uses only integer math, so it
+// is exact.
+func (e Element) HasCollided(obstacle Element) bool {
+	dx := int64(obstacle.Coords.PosX - e.Coords.PosX)
+	dy := int64(obstacle.Coords.PosY - e.Coords.PosY)
+	// compares the doubled distances to avoid rounding the sum of the radius
+	minDistance := int64(e.Size + obstacle.Size)
+	return 4*(dx*dx+dy*dy) < minDistance*minDistance
+}
+
+// SegmentCollides detects if the element body, increased by the margin, touches the segment from `a` to `b`.
+// The check uses only integer math, so it is exact.
+func (e Element) SegmentCollides(a, b physics.Point, margin int) bool {
+	// the doubled radius avoids rounding half sizes, so all distances are doubled as well
+	doubledRadius := int64(e.Size + 2*margin)
+	maxSq := doubledRadius * doubledRadius
+	abX, abY := int64(b.PosX-a.PosX), int64(b.PosY-a.PosY)
+	acX, acY := int64(e.Coords.PosX-a.PosX), int64(e.Coords.PosY-a.PosY)
+	lengthSq := abX*abX + abY*abY
+	dot := acX*abX + acY*abY
+	if lengthSq == 0 || dot <= 0 {
+		return 4*(acX*acX+acY*acY) <= maxSq
+	}
+	if dot >= lengthSq {
+		bcX, bcY := acX-abX, acY-abY
+		return 4*(bcX*bcX+bcY*bcY) <= maxSq
+	}
+	// the squared distance to the line is cross^2 / |ab|^2, compared without dividing
+	cross := acX*abY - acY*abX
+	return 4*cross*cross <= maxSq*lengthSq
+}
+
+// Move moves the element by its velocity
+func (e *Element) Move() {
+	e.Coords = e.Velocity.Target(e.Coords)
+}
@@ -0,0 +1,108 @@
+package fixed
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestElement_HasCollided(t *testing.T) {
+	a := Element{Size: 9, Coords: physics.Point{}}
+	b := Element{Size: 12, Coords: physics.Point{PosX: 10}}
+	assert.True(t, a.HasCollided(b))
+	b.Coords = physics.Point{PosX: 11}
+	assert.False(t, a.HasCollided(b))
+	b.Coords = physics.Point{PosX: 5, PosY: 9}
+	assert.True(t, a.HasCollided(b))
+	b.Coords = physics.Point{PosX: 5, PosY: 10}
+	assert.False(t, a.HasCollided(b))
+}
+
+func TestElement_SegmentCollides(t *testing.T) {
+	e := Element{Size: 8, Coords: physics.Point{PosX: 0, PosY: 5}}
+	assert.True(t, e.SegmentCollides(physics.Point{PosX: -10, PosY: 0}, physics.Point{PosX: 10, PosY: 0}, 1))
+	assert.False(t, e.SegmentCollides(physics.Point{PosX: -10, PosY: 0}, physics.Point{PosX: 10, PosY: 0}, 0))
+	assert.True(t, e.SegmentCollides(physics.Point{PosX: -10, PosY: 1}, physics.Point{PosX: 10, PosY: 1}, 0))
+	// segment ends before the element
+	assert.False(t, e.SegmentCollides(physics.Point{PosX: 10, PosY: 5}, physics.Point{PosX: 20, PosY: 5}, 0))
+	assert.True(t, e.SegmentCollides(physics.Point{PosX: 4, PosY: 5}, physics.Point{PosX: 20, PosY: 5}, 0))
+	assert.True(t, e.SegmentCollides(physics.Point{PosX: 0, PosY: 5}, physics.Point{PosX: 0, PosY: 5}, 0))
+}
+
+func TestVelocity_MatchesPhysics(t *testing.T) {
+	velocity := NewVelocityTo(physics.Point{}, physics.Point{PosX: 3, PosY: 4}, FromInt(50))
+	converted := velocity.Physics()
+	assert.Equal(t, velocity.Target(physics.Point{PosX: 10, PosY: 10}), converted.Target(physics.Point{PosX: 10, PosY: 10}))
+	assert.Equal(t, velocity, FromVelocity(converted))
+
+	a := NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, FromInt(100))
+	b := NewVelocityTo(physics.Point{}, physics.Point{PosY: 1}, FromInt(100))
+	sum := a.Add(b)
+	assert.Equal(t, 141, sum.Speed.Round())
+	assert.Equal(t, FromFloat(70.71068), sum.Direction.X)
+
+	inverse := NewVelocityTo(physics.Point{}, physics.Point{PosX: -1}, FromInt(100))
+	assert.Equal(t, Num(0), a.Add(inverse).Speed)
+}
+
+// simulateTrajectories runs a set of scenarios and describes each turn using the raw fixed-point values
+func simulateTrajectories() []byte {
+	out := &bytes.Buffer{}
+	kicks := []struct {
+		name     string
+		from     physics.Point
+		velocity Velocity
+	}{
+		{"diagonal-kick", physics.Point{PosX: 10000, PosY: 5000}, NewVelocityTo(physics.Point{}, physics.Point{PosX: 7, PosY: 3}, FromInt(units.BallMaxSpeed))},
+		{"steep-kick", physics.Point{PosX: 1234, PosY: 8765}, NewVelocityTo(physics.Point{}, physics.Point{PosX: 1, PosY: -13}, FromInt(333))},
+		{"weak-kick", physics.Point{PosX: 19000, PosY: 100}, NewVelocityTo(physics.Point{}, physics.Point{PosX: -5, PosY: 2}, FromInt(37))},
+	}
+	for _, kick := range kicks {
+		ball := Element{Size: units.BallSize, Coords: kick.from, Velocity: kick.velocity}
+		for turn := 1; ball.Velocity.Speed > 0; turn++ {
+			StepBall(&ball)
+			fmt.Fprintf(out, "%s %d %d %d %s\n", kick.name, turn, ball.Coords.PosX, ball.Coords.PosY, ball.Velocity)
+		}
+	}
+
+	// a player curving its run while a ball crosses its path
+	player := Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 5000, PosY: 2000}, Velocity: NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, FromInt(units.PlayerMaxSpeed))}
+	ball := Element{Size: units.BallSize, Coords: physics.Point{PosX: 6500, PosY: 6000}, Velocity: NewVelocityTo(physics.Point{}, physics.Point{PosX: -1, PosY: -4}, FromInt(300))}
+	curve := NewVelocityTo(physics.Point{}, physics.Point{PosY: 1}, FromInt(15))
+	for turn := 1; turn <= 30; turn++ {
+		from := player.Coords
+		player.Velocity = player.Velocity.Add(curve)
+		if player.Velocity.Speed > FromInt(units.PlayerMaxSpeed) {
+			player.Velocity.Speed = FromInt(units.PlayerMaxSpeed)
+		}
+		player.Move()
+		StepBall(&ball)
+		fmt.Fprintf(out, "run %d %d %d %s collided=%t crossed=%t\n", turn, player.Coords.PosX, player.Coords.PosY, player.Velocity,
+			player.HasCollided(ball), ball.SegmentCollides(from, player.Coords, 0))
+	}
+	return out.Bytes()
+}
+
+func TestTrajectories_Golden(t *testing.T) {
+	golden := filepath.Join("testdata", "trajectories.golden")
+	result := simulateTrajectories()
+	if *update {
+		if err := ioutil.WriteFile(golden, result, 0644); err != nil {
+			t.Fatalf("fail on updating the golden file: %s", err)
+		}
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("fail on reading the golden file: %s", err)
+	}
+	assert.Equal(t, string(expected), string(result))
+	assert.Equal(t, result, simulateTrajectories(), "the simulation must be identical in every run")
+}
@@ -0,0 +1,118 @@
+package fixed
+
+import (
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+)
+
+// Engine runs the deterministic operations that depend on the game rules, as physics.Engine does for the float64
+// ones
+type Engine struct {
+	// Rules are the game values used by the operations
+	Rules units.Rules
+}
+
+// NewEngine creates an engine that follows the rules
+func NewEngine(rules units.Rules) Engine {
+	return Engine{Rules: rules}
+}
+
+// defaultEngine is used by the package functions
+var defaultEngine = NewEngine(units.DefaultRules())
+
+// StepBall moves the ball by its velocity, reflecting it on the field borders, and then applies the ball
+// deceleration, see Engine.StepBall
+func StepBall(ball *Element) {
+	defaultEngine.StepBall(ball)
+}
+
+// StepBall moves the ball by its velocity, reflecting it on the field borders, and then applies the ball
+// deceleration. The ball stops when its speed is at or below BallMinSpeed, as predicted by physics.BallTrajectory.
+func (e Engine) StepBall(ball *Element) {
+	ball.Coords, ball.Velocity = e.ReflectOnBorders(ball.Coords, ball.Velocity, ball.Size)
+	ball.Velocity = ball.Velocity.Decelerate(FromFloat(e.Rules.BallDeceleration))
+	if ball.Velocity.Speed <= FromFloat(e.Rules.BallMinSpeed) {
+		ball.Velocity.Speed = 0
+	}
+}
+
+// ReflectOnBorders is the deterministic version of physics.Engine.ReflectOnBorders: it finds the point reached by a
+// ball of size `size` moving from `from` with the velocity, reflecting the position and the direction on the borders
+// the ball would cross, except the left and right borders when the ball crosses them through the goal mouth.
+func (e Engine) ReflectOnBorders(from physics.Point, velocity Velocity, size int) (physics.Point, Velocity) {
+	movement := velocity.Movement()
+	if movement.IsZero() {
+		return from, velocity
+	}
+	radius := FromInt(size) / 2
+	minX, maxX := radius, FromInt(e.Rules.FieldWidth)-radius
+	minY, maxY := radius, FromInt(e.Rules.FieldHeight)-radius
+
+	x, y := FromInt(from.PosX), FromInt(from.PosY)
+	// dx and dy are the movement left after each bounce
+	dx, dy := movement.X, movement.Y
+	direction := velocity.Direction
+	reflected := false
+	throughGoal := false
+	// a ball may bounce on more than one border in the same turn (e.g. close to the corners), so the borders are
+	// reflected in the order the ball reaches them
+	for i := 0; i < 4; i++ {
+		hitX, okX := hitTime(x, dx, minX, maxX)
+		okX = okX && !throughGoal
+		hitY, okY := hitTime(y, dy, minY, maxY)
+		if !okX && !okY {
+			break
+		}
+		if okX && (!okY || hitX <= hitY) {
+			if e.crossesGoalMouth(y + dy.Mul(hitX)) {
+				// the ball leaves the field through the goal, so only the top and bottom borders are left
+				throughGoal = true
+				continue
+			}
+			x, y = x+dx.Mul(hitX), y+dy.Mul(hitX)
+			dx, dy = -dx.Mul(One-hitX), dy.Mul(One-hitX)
+			direction.X = -direction.X
+			reflected = true
+			continue
+		}
+		x, y = x+dx.Mul(hitY), y+dy.Mul(hitY)
+		dx, dy = dx.Mul(One-hitY), -dy.Mul(One-hitY)
+		direction.Y = -direction.Y
+		reflected = true
+	}
+	if reflected {
+		velocity.Direction = direction.Normalize()
+	}
+	return physics.Point{PosX: (x + dx).Round(), PosY: (y + dy).Round()}, velocity
+}
+
+// ClampPlayerTarget returns the point reached by a player moving from `from` with the velocity, limited to the
+// playable area as physics.Engine.ClampPlayerTarget does
+func (e Engine) ClampPlayerTarget(from physics.Point, velocity Velocity) physics.Point {
+	// the clamp only uses integer math
+	return physics.NewEngine(e.Rules).ClampToField(velocity.Target(from), e.Rules.PlayerSize)
+}
+
+// hitTime finds the fraction of the movement `delta` from `position` when the ball reaches the limits. The second
+// value is false when the movement ends before the limits.
+func hitTime(position, delta, min, max Num) (Num, bool) {
+	switch {
+	case delta > 0 && position+delta > max:
+		return maxNum(0, (max - position).Div(delta)), true
+	case delta < 0 && position+delta < min:
+		return maxNum(0, (min - position).Div(delta)), true
+	}
+	return 0, false
+}
+
+// crossesGoalMouth checks if the ball crossing the goal line at `crossY` passes between the goal poles
+func (e Engine) crossesGoalMouth(crossY Num) bool {
+	return crossY >= FromInt(e.Rules.GoalMinY()) && crossY <= FromInt(e.Rules.GoalMaxY())
+}
+
+func maxNum(a, b Num) Num {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,55 @@
+package fixed
+
+import (
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEngine_StepBall(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.BallDeceleration = 20
+	engine := NewEngine(rules)
+	ball := Element{Size: units.BallSize, Coords: physics.Point{PosX: 1000, PosY: 1000}, Velocity: NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, FromInt(50))}
+	engine.StepBall(&ball)
+	assert.Equal(t, physics.Point{PosX: 1050, PosY: 1000}, ball.Coords)
+	assert.Equal(t, FromInt(30), ball.Velocity.Speed)
+	engine.StepBall(&ball)
+	engine.StepBall(&ball)
+	assert.Equal(t, physics.Point{PosX: 1090, PosY: 1000}, ball.Coords)
+	// the speed reaches BallMinSpeed
+	ball.Velocity.Speed = FromFloat(rules.BallMinSpeed + rules.BallDeceleration)
+	engine.StepBall(&ball)
+	assert.Equal(t, Num(0), ball.Velocity.Speed)
+}
+
+func TestEngine_ReflectOnBorders(t *testing.T) {
+	engine := NewEngine(units.DefaultRules())
+	radius := units.BallSize / 2
+	from := physics.Point{PosX: 5000, PosY: units.FieldHeight - radius - 30}
+	velocity := NewVelocityTo(physics.Point{}, physics.Point{PosX: 1, PosY: 1}, FromInt(100))
+	target, reflected := engine.ReflectOnBorders(from, velocity, units.BallSize)
+
+	expected, expectedVelocity, _ := physics.ReflectOnBorders(from, velocity.Physics(), units.BallSize)
+	assert.Equal(t, expected, target)
+	assert.True(t, reflected.Direction.Y < 0)
+	assert.Equal(t, FromVelocity(expectedVelocity).Direction.Y.Round(), reflected.Direction.Y.Round())
+	assert.Equal(t, velocity.Speed, reflected.Speed)
+
+	// the ball is not reflected when it crosses the goal line through the goal mouth
+	from = physics.Point{PosX: units.FieldWidth - radius - 10, PosY: units.FieldHeight / 2}
+	velocity = NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, FromInt(100))
+	target, reflected = engine.ReflectOnBorders(from, velocity, units.BallSize)
+	assert.Equal(t, physics.Point{PosX: from.PosX + 100, PosY: from.PosY}, target)
+	assert.Equal(t, velocity, reflected)
+}
+
+func TestEngine_ClampPlayerTarget(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.FieldWidth = 10000
+	engine := NewEngine(rules)
+	velocity := NewVelocityTo(physics.Point{}, physics.Point{PosX: 1}, FromInt(100))
+	assert.Equal(t, physics.Point{PosX: 9100, PosY: 5000}, engine.ClampPlayerTarget(physics.Point{PosX: 9000, PosY: 5000}, velocity))
+	assert.Equal(t, physics.Point{PosX: 10000 - units.PlayerSize/2, PosY: 5000}, engine.ClampPlayerTarget(physics.Point{PosX: 9950, PosY: 5000}, velocity))
+}
@@ -0,0 +1,109 @@
+// Package fixed implements a deterministic mode for the physics operations. All values are fixed-point numbers
+// stored in integers, and every rounding is explicitly specified (half away from zero), so the same operations
+// produce bit-identical results in any platform and Go version, which is not guaranteed by float64 math.
+//
+// The values are converted from and to the physics package types, so the fixed mode may be used only where the
+// simulation must be reproducible: the simulator uses it when sim.Config.Deterministic is set, and replay.Verify
+// does the same for the replays whose header is marked as deterministic. The operations that depend on the game
+// rules are run by an Engine, as in the physics package.
+package fixed
+
+import (
+	"fmt"
+	"math"
+)
+
+// Shift is the number of fractional bits of the fixed-point numbers
+const Shift = 16
+
+// One is the number one in fixed-point representation
+const One Num = 1 << Shift
+
+// Num is a fixed-point number with Shift fractional bits. It supports the field coordinates range without
+// overflowing, including products of two coordinates.
+type Num int64
+
+// FromInt converts an integer to a fixed-point number
+func FromInt(i int) Num {
+	return Num(int64(i) << Shift)
+}
+
+// FromFloat converts a float to the closest fixed-point number. This is the only operation that depends on float
+// math, so it should only be used to convert input values.
+func FromFloat(f float64) Num {
+	return Num(math.Round(f * float64(One)))
+}
+
+// Float converts the number to float64
+func (n Num) Float() float64 {
+	return float64(n) / float64(One)
+}
+
+// Round returns the closest integer, rounding half away from zero
+func (n Num) Round() int {
+	return int(divRound(int64(n), int64(One)))
+}
+
+// Mul multiplies two numbers
+func (n Num) Mul(m Num) Num {
+	return Num(divRound(int64(n)*int64(m), int64(One)))
+}
+
+// Div divides the number by `m`. It panics if `m` is zero
+func (n Num) Div(m Num) Num {
+	return Num(divRound(int64(n)<<Shift, int64(m)))
+}
+
+// Abs returns the absolute value of the number
+func (n Num) Abs() Num {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// String returns the string representation of the number
+func (n Num) String() string {
+	return fmt.Sprintf("%.5f", n.Float())
+}
+
+// Hypot returns Sqrt(x*x + y*y) without overflowing for the field coordinates range
+func Hypot(x, y Num) Num {
+	ax, ay := uint64(x.Abs()), uint64(y.Abs())
+	return Num(isqrt(ax*ax + ay*ay))
+}
+
+// divRound divides two integers rounding half away from zero
+func divRound(a, b int64) int64 {
+	if b < 0 {
+		a, b = -a, -b
+	}
+	if a >= 0 {
+		return (a + b/2) / b
+	}
+	return -((-a + b/2) / b)
+}
+
+// isqrt returns the integer square root of `n`, rounded to the closest integer
+func isqrt(n uint64) uint64 {
+	var root uint64
+	bit := uint64(1) << 62
+	for bit > n {
+		bit >>= 2
+	}
+	rest := n
+	for bit != 0 {
+		if rest >= root+bit {
+			rest -= root + bit
+			root = root>>1 + bit
+		} else {
+			root >>= 1
+		}
+		bit >>= 2
+	}
+	// the remainder tells if the real root is closer to the next integer
+	if rest > root {
+		root++
+	}
+	return root
+}
@@ -0,0 +1,38 @@
+package fixed
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestNum_Conversions(t *testing.T) {
+	assert.Equal(t, Num(65536), FromInt(1))
+	assert.Equal(t, Num(-98304), FromFloat(-1.5))
+	assert.Equal(t, 2.5, FromFloat(2.5).Float())
+	assert.Equal(t, 3, FromFloat(2.5).Round())
+	assert.Equal(t, -3, FromFloat(-2.5).Round())
+	assert.Equal(t, 2, FromFloat(2.49).Round())
+	assert.Equal(t, "1.50000", FromFloat(1.5).String())
+}
+
+func TestNum_Operations(t *testing.T) {
+	assert.Equal(t, FromFloat(3.75), FromFloat(1.5).Mul(FromFloat(2.5)))
+	assert.Equal(t, FromFloat(-3.75), FromFloat(-1.5).Mul(FromFloat(2.5)))
+	assert.Equal(t, FromFloat(0.6), FromInt(3).Div(FromInt(5)))
+	assert.Equal(t, FromInt(-2), FromInt(4).Div(FromInt(-2)))
+	assert.Equal(t, FromInt(5), Hypot(FromInt(3), FromInt(-4)))
+	assert.Equal(t, FromInt(20000), Hypot(FromInt(20000), 0))
+}
+
+func TestIsqrt(t *testing.T) {
+	assert.Equal(t, uint64(0), isqrt(0))
+	assert.Equal(t, uint64(1), isqrt(1))
+	assert.Equal(t, uint64(2), isqrt(3))
+	assert.Equal(t, uint64(2), isqrt(6))
+	assert.Equal(t, uint64(3), isqrt(7))
+	assert.Equal(t, uint64(1<<32), isqrt(math.MaxUint64))
+	for n := uint64(0); n < 5000; n++ {
+		assert.Equal(t, uint64(math.Round(math.Sqrt(float64(n)))), isqrt(n), "sqrt(%d)", n)
+	}
+}
@@ -0,0 +1,86 @@
+package fixed
+
+import (
+	"errors"
+	"github.com/lugobots/arena/physics"
+)
+
+// Vector is the deterministic version of physics.Vector
+type Vector struct {
+	X Num
+	Y Num
+}
+
+// NewVector creates a vector from the point `from` to the point `to`
+func NewVector(from, to physics.Point) (Vector, error) {
+	v := Vector{X: FromInt(to.PosX - from.PosX), Y: FromInt(to.PosY - from.PosY)}
+	if v.IsZero() {
+		return Vector{}, errors.New("vector can not have zero length")
+	}
+	return v, nil
+}
+
+// FromVector converts a physics vector
+func FromVector(v *physics.Vector) Vector {
+	return Vector{X: FromFloat(v.GetX()), Y: FromFloat(v.GetY())}
+}
+
+// Physics converts the vector to a physics vector
+func (v Vector) Physics() *physics.Vector {
+	vector, _ := physics.NewVector(physics.Point{}, physics.Point{PosX: 1})
+	vector.SetY(v.Y.Float())
+	vector.SetX(v.X.Float())
+	return vector
+}
+
+// IsZero returns true when both coordinates are zero
+func (v Vector) IsZero() bool {
+	return v.X == 0 && v.Y == 0
+}
+
+// Length returns the vector length
+func (v Vector) Length() Num {
+	return Hypot(v.X, v.Y)
+}
+
+// Scale multiplies the vector coordinates by `t`
+func (v Vector) Scale(t Num) Vector {
+	return Vector{X: v.X.Mul(t), Y: v.Y.Mul(t)}
+}
+
+// SetLength returns a vector with the same direction and the length `length`. Zero vectors are not changed
+func (v Vector) SetLength(length Num) Vector {
+	current := v.Length()
+	if current == 0 {
+		return v
+	}
+	return Vector{
+		X: Num(divRound(int64(v.X)*int64(length), int64(current))),
+		Y: Num(divRound(int64(v.Y)*int64(length), int64(current))),
+	}
+}
+
+// Normalize returns a vector with the same direction and magnitude 100, as physics.Vector.Normalize does
+func (v Vector) Normalize() Vector {
+	return v.SetLength(FromInt(100))
+}
+
+// Add returns the sum of the vectors
+func (v Vector) Add(other Vector) Vector {
+	return Vector{X: v.X + other.X, Y: v.Y + other.Y}
+}
+
+// Sub returns the difference of the vectors
+func (v Vector) Sub(other Vector) Vector {
+	return Vector{X: v.X - other.X, Y: v.Y - other.Y}
+}
+
+// Invert returns the vector with the opposite direction
+func (v Vector) Invert() Vector {
+	return Vector{X: -v.X, Y: -v.Y}
+}
+
+// TargetFrom returns the point reached by moving from `p` by the vector
+func (v Vector) TargetFrom(p physics.Point) physics.Point {
+	return physics.Point{PosX: p.PosX + v.X.Round(), PosY: p.PosY + v.Y.Round()}
+}
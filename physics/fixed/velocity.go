@@ -0,0 +1,83 @@
+package fixed
+
+import (
+	"fmt"
+	"github.com/lugobots/arena/physics"
+)
+
+// Velocity is the deterministic version of physics.Velocity
+type Velocity struct {
+	Direction Vector
+	Speed     Num
+}
+
+// NewVelocityTo creates a velocity pointing from the point `from` to the point `to` with the speed, as
+// physics.NewVelocityTo does. The velocity has no direction nor speed when the points are the same.
+func NewVelocityTo(from, to physics.Point, speed Num) Velocity {
+	direction, err := NewVector(from, to)
+	if err != nil {
+		return Velocity{}
+	}
+	return Velocity{Direction: direction.Normalize(), Speed: speed}
+}
+
+// FromVelocity converts a physics velocity
+func FromVelocity(v physics.Velocity) Velocity {
+	velocity := Velocity{Speed: FromFloat(v.Speed)}
+	if v.Direction != nil {
+		velocity.Direction = FromVector(v.Direction)
+	}
+	return velocity
+}
+
+// Physics converts the velocity to a physics velocity
+func (v Velocity) Physics() physics.Velocity {
+	direction := physics.East
+	if !v.Direction.IsZero() {
+		direction = *v.Direction.Physics()
+	}
+	velocity := physics.NewZeroedVelocity(direction)
+	velocity.Speed = v.Speed.Float()
+	return velocity
+}
+
+// Movement returns the vector that moves an element during one turn
+func (v Velocity) Movement() Vector {
+	if v.Speed == 0 || v.Direction.IsZero() {
+		return Vector{}
+	}
+	return v.Direction.SetLength(v.Speed)
+}
+
+// Target returns the target point from the point `from` considering the distance as the speed
+func (v Velocity) Target(from physics.Point) physics.Point {
+	return v.Movement().TargetFrom(from)
+}
+
+// Add returns the sum of the velocities, following the same rules of physics.Velocity.Add
+func (v Velocity) Add(other Velocity) Velocity {
+	current := v.Movement()
+	added := other.Movement()
+	if added.Invert() == current {
+		return Velocity{Direction: v.Direction.Invert().Normalize(), Speed: 0}
+	}
+	sum := current.Add(added)
+	if sum.IsZero() {
+		return Velocity{Direction: v.Direction.Normalize(), Speed: 0}
+	}
+	return Velocity{Direction: sum.Normalize(), Speed: sum.Length()}
+}
+
+// Decelerate returns the velocity with the speed reduced by `amount`, never going below zero
+func (v Velocity) Decelerate(amount Num) Velocity {
+	v.Speed -= amount
+	if v.Speed < 0 {
+		v.Speed = 0
+	}
+	return v
+}
+
+// String returns the string representation of the velocity with the raw fixed values
+func (v Velocity) String() string {
+	return fmt.Sprintf("[%dx,%dy => %ds]", v.Direction.X, v.Direction.Y, v.Speed)
+}
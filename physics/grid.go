@@ -0,0 +1,238 @@
+package physics
+
+import (
+	"github.com/lugobots/arena/units"
+	"math"
+	"sort"
+)
+
+// DefaultGridCellSize is the cell size used by the grid when no other size is specified
+const DefaultGridCellSize = 4 * units.PlayerSize
+
+// Grid is a uniform grid spatial index over elements. It speeds up the queries that would otherwise scan all
+// elements, e.g. finding the nearest elements to a point. The grid keeps pointers to the elements, so it must be
+// rebuilt when the elements move.
+type Grid struct {
+	width    int
+	height   int
+	cellSize int
+	cols     int
+	rows     int
+	cells    [][]*Element
+	// outside keeps the elements out of the field borders, they are always checked
+	outside []*Element
+	count   int
+	// maxRadius is the radius of the largest element inserted, the queries about the bodies look this far
+	maxRadius float64
+}
+
+// NewGrid creates a grid that covers the field with square cells of size `cellSize`
+func NewGrid(cellSize int) *Grid {
+	return NewGridFromRules(units.DefaultRules(), cellSize)
+}
+
+// NewGridFromRules creates a grid that covers the field defined by the rules with square cells of size `cellSize`
+func NewGridFromRules(rules units.Rules, cellSize int) *Grid {
+	if cellSize <= 0 {
+		cellSize = DefaultGridCellSize
+	}
+	g := &Grid{
+		width:    rules.FieldWidth,
+		height:   rules.FieldHeight,
+		cellSize: cellSize,
+		cols:     rules.FieldWidth/cellSize + 1,
+		rows:     rules.FieldHeight/cellSize + 1,
+	}
+	g.cells = make([][]*Element, g.cols*g.rows)
+	return g
+}
+
+// NewGridWith creates a grid with the default cell size indexing the elements
+func NewGridWith(elements ...*Element) *Grid {
+	g := NewGrid(DefaultGridCellSize)
+	for _, e := range elements {
+		g.Insert(e)
+	}
+	return g
+}
+
+// Insert adds an element to the grid
+func (g *Grid) Insert(e *Element) {
+	g.count++
+	g.maxRadius = math.Max(g.maxRadius, float64(e.Size)/2)
+	col, row, ok := g.cellOf(e.Coords)
+	if !ok {
+		g.outside = append(g.outside, e)
+		return
+	}
+	idx := row*g.cols + col
+	g.cells[idx] = append(g.cells[idx], e)
+}
+
+// Len returns the number of elements in the grid
+func (g *Grid) Len() int {
+	return g.count
+}
+
+// Clear removes all elements from the grid keeping its cells allocated
+func (g *Grid) Clear() {
+	for i := range g.cells {
+		g.cells[i] = g.cells[i][:0]
+	}
+	g.outside = g.outside[:0]
+	g.count = 0
+	g.maxRadius = 0
+}
+
+// Nearest finds the `k` elements nearest to the point, ordered by the distance from their centers to the point.
+// The `filter` function may be used to ignore some elements (e.g. teammates), it may be nil.
+func (g *Grid) Nearest(p Point, k int, filter func(*Element) bool) []*Element {
+	if k <= 0 || g.count == 0 {
+		return nil
+	}
+	best := make([]rankedElement, 0, k)
+	consider := func(e *Element) {
+		if filter != nil && !filter(e) {
+			return
+		}
+		best = insertRanked(best, rankedElement{element: e, distance: p.DistanceTo(e.Coords)}, k)
+	}
+	for _, e := range g.outside {
+		consider(e)
+	}
+	centerCol, centerRow, _ := g.clampedCellOf(p)
+	maxRing := g.cols
+	if g.rows > maxRing {
+		maxRing = g.rows
+	}
+	for ring := 0; ring <= maxRing; ring++ {
+		g.visitRing(centerCol, centerRow, ring, consider)
+		// elements in the next rings are at least `ring * cellSize` away
+		if len(best) == k && best[k-1].distance <= float64(ring*g.cellSize) {
+			break
+		}
+	}
+	found := make([]*Element, len(best))
+	for i, ranked := range best {
+		found[i] = ranked.element
+	}
+	return found
+}
+
+type rankedElement struct {
+	element  *Element
+	distance float64
+}
+
+// insertRanked inserts the element keeping the list ordered by distance and limited to `k` elements
+func insertRanked(list []rankedElement, item rankedElement, k int) []rankedElement {
+	if len(list) == k && item.distance >= list[k-1].distance {
+		return list
+	}
+	i := len(list)
+	for i > 0 && list[i-1].distance > item.distance {
+		i--
+	}
+	if len(list) < k {
+		list = append(list, rankedElement{})
+	}
+	copy(list[i+1:], list[i:len(list)-1])
+	list[i] = item
+	return list
+}
+
+// WithinRadius finds all elements whose centers are within the radius from the point, ordered by distance
+func (g *Grid) WithinRadius(p Point, radius float64, filter func(*Element) bool) []*Element {
+	var found []*Element
+	g.visitBox(p.PosX-int(math.Ceil(radius)), p.PosY-int(math.Ceil(radius)), p.PosX+int(math.Ceil(radius)), p.PosY+int(math.Ceil(radius)), func(e *Element) {
+		if (filter == nil || filter(e)) && p.DistanceTo(e.Coords) <= radius {
+			found = append(found, e)
+		}
+	})
+	sortByDistance(found, p)
+	return found
+}
+
+// AlongSegment finds all elements whose bodies touch the segment from `a` to `b`, considering the margin.
+// The elements are ordered by their distance to `a`.
+func (g *Grid) AlongSegment(a, b Point, margin float64, filter func(*Element) bool) []*Element {
+	var found []*Element
+	// the centers of the elements touching the segment are at most this far from it
+	reach := int(math.Ceil(margin + g.maxRadius))
+	minX, maxX := a.PosX, b.PosX
+	if minX > maxX {
+		minX, maxX = maxX, minX
+	}
+	minY, maxY := a.PosY, b.PosY
+	if minY > maxY {
+		minY, maxY = maxY, minY
+	}
+	g.visitBox(minX-reach, minY-reach, maxX+reach, maxY+reach, func(e *Element) {
+		if filter != nil && !filter(e) {
+			return
+		}
+		if e.Coords.DistanceToSegment(a, b) <= float64(e.Size)/2+margin {
+			found = append(found, e)
+		}
+	})
+	sortByDistance(found, a)
+	return found
+}
+
+// visitBox calls the visitor for each element in the cells that overlap the box, and for the elements out of the field
+func (g *Grid) visitBox(minX, minY, maxX, maxY int, visitor func(*Element)) {
+	minCol, minRow, _ := g.clampedCellOf(Point{PosX: minX, PosY: minY})
+	maxCol, maxRow, _ := g.clampedCellOf(Point{PosX: maxX, PosY: maxY})
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			for _, e := range g.cells[row*g.cols+col] {
+				visitor(e)
+			}
+		}
+	}
+	for _, e := range g.outside {
+		visitor(e)
+	}
+}
+
+// visitRing calls the visitor for each element in the cells at exactly `ring` cells of distance from the center cell
+func (g *Grid) visitRing(centerCol, centerRow, ring int, visitor func(*Element)) {
+	for row := centerRow - ring; row <= centerRow+ring; row++ {
+		if row < 0 || row >= g.rows {
+			continue
+		}
+		step := 1
+		if row != centerRow-ring && row != centerRow+ring {
+			// only the first and last columns belong to the ring
+			step = 2 * ring
+		}
+		for col := centerCol - ring; col <= centerCol+ring; col += step {
+			if col >= 0 && col < g.cols {
+				for _, e := range g.cells[row*g.cols+col] {
+					visitor(e)
+				}
+			}
+		}
+	}
+}
+
+func (g *Grid) cellOf(p Point) (int, int, bool) {
+	if p.PosX < 0 || p.PosY < 0 || p.PosX > g.width || p.PosY > g.height {
+		return 0, 0, false
+	}
+	return p.PosX / g.cellSize, p.PosY / g.cellSize, true
+}
+
+func (g *Grid) clampedCellOf(p Point) (int, int, bool) {
+	col, row, ok := g.cellOf(Point{
+		PosX: clampInt(p.PosX, 0, g.width),
+		PosY: clampInt(p.PosY, 0, g.height),
+	})
+	return col, row, ok
+}
+
+func sortByDistance(elements []*Element, p Point) {
+	sort.SliceStable(elements, func(i, j int) bool {
+		return p.DistanceTo(elements[i].Coords) < p.DistanceTo(elements[j].Coords)
+	})
+}
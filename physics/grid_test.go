@@ -0,0 +1,173 @@
+package physics
+
+import (
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func randomElements(r *rand.Rand, n int) []*Element {
+	elements := make([]*Element, n)
+	for i := range elements {
+		elements[i] = &Element{
+			Size:   units.PlayerSize,
+			Coords: Point{PosX: r.Intn(units.FieldWidth + 1), PosY: r.Intn(units.FieldHeight + 1)},
+		}
+	}
+	return elements
+}
+
+func linearNearest(elements []*Element, p Point, k int) []*Element {
+	sorted := append([]*Element{}, elements...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return p.DistanceTo(sorted[i].Coords) < p.DistanceTo(sorted[j].Coords)
+	})
+	if len(sorted) > k {
+		sorted = sorted[:k]
+	}
+	return sorted
+}
+
+func TestGrid_Nearest(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	elements := randomElements(r, 22)
+	grid := NewGridWith(elements...)
+	assert.Equal(t, 22, grid.Len())
+
+	for i := 0; i < 200; i++ {
+		p := Point{PosX: r.Intn(units.FieldWidth+2000) - 1000, PosY: r.Intn(units.FieldHeight+2000) - 1000}
+		expected := linearNearest(elements, p, 3)
+		found := grid.Nearest(p, 3, nil)
+		if assert.Len(t, found, 3) {
+			for j := range expected {
+				assert.Equal(t, p.DistanceTo(expected[j].Coords), p.DistanceTo(found[j].Coords))
+			}
+		}
+	}
+}
+
+func TestGrid_NearestFilter(t *testing.T) {
+	near := &Element{Size: units.PlayerSize, Coords: Point{PosX: 1000, PosY: 1000}}
+	far := &Element{Size: units.PlayerSize, Coords: Point{PosX: 15000, PosY: 9000}}
+	outside := &Element{Size: units.BallSize, Coords: Point{PosX: -100, PosY: 1000}}
+	grid := NewGridWith(near, far, outside)
+
+	assert.Equal(t, []*Element{near, outside, far}, grid.Nearest(Point{PosX: 900, PosY: 1000}, 5, nil))
+	assert.Equal(t, []*Element{far}, grid.Nearest(Point{PosX: 900, PosY: 1000}, 1, func(e *Element) bool {
+		return e != near && e != outside
+	}))
+	assert.Nil(t, grid.Nearest(Point{}, 0, nil))
+
+	grid.Clear()
+	assert.Nil(t, grid.Nearest(Point{}, 1, nil))
+}
+
+func TestGrid_WithinRadius(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	elements := randomElements(r, 22)
+	grid := NewGrid(units.PlayerSize)
+	for _, e := range elements {
+		grid.Insert(e)
+	}
+	p := Point{PosX: 10000, PosY: 5000}
+	radius := 3000.0
+
+	var expected []*Element
+	for _, e := range linearNearest(elements, p, len(elements)) {
+		if p.DistanceTo(e.Coords) <= radius {
+			expected = append(expected, e)
+		}
+	}
+	assert.Equal(t, expected, grid.WithinRadius(p, radius, nil))
+}
+
+func TestGrid_AlongSegment(t *testing.T) {
+	onPath := &Element{Size: units.PlayerSize, Coords: Point{PosX: 5000, PosY: 5100}}
+	closeToPath := &Element{Size: units.PlayerSize, Coords: Point{PosX: 3000, PosY: 5300}}
+	offPath := &Element{Size: units.PlayerSize, Coords: Point{PosX: 4000, PosY: 7000}}
+	beyondEnd := &Element{Size: units.PlayerSize, Coords: Point{PosX: 9000, PosY: 5000}}
+	grid := NewGridWith(onPath, closeToPath, offPath, beyondEnd)
+
+	a := Point{PosX: 1000, PosY: 5000}
+	b := Point{PosX: 8000, PosY: 5000}
+	assert.Equal(t, []*Element{onPath}, grid.AlongSegment(a, b, 0, nil))
+	assert.Equal(t, []*Element{closeToPath, onPath}, grid.AlongSegment(a, b, 200, nil))
+
+	// the cells smaller than the elements do not hide the elements whose centers are cells away from the segment
+	small := NewGrid(50)
+	for _, e := range []*Element{onPath, closeToPath, offPath, beyondEnd} {
+		small.Insert(e)
+	}
+	assert.Equal(t, []*Element{onPath}, small.AlongSegment(a, b, 0, nil))
+	assert.Equal(t, []*Element{closeToPath, onPath}, small.AlongSegment(a, b, 200, nil))
+	assert.Equal(t, []*Element{beyondEnd}, small.AlongSegment(Point{PosX: 9000, PosY: 5150}, Point{PosX: 9500, PosY: 5150}, 0, nil))
+}
+
+func BenchmarkGrid_Nearest(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	elements := randomElements(r, 22)
+	grid := NewGridWith(elements...)
+	points := make([]Point, 1024)
+	for i := range points {
+		points[i] = Point{PosX: r.Intn(units.FieldWidth), PosY: r.Intn(units.FieldHeight)}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		grid.Nearest(points[i%len(points)], 1, nil)
+	}
+}
+
+func BenchmarkLinear_Nearest(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	elements := randomElements(r, 22)
+	points := make([]Point, 1024)
+	for i := range points {
+		points[i] = Point{PosX: r.Intn(units.FieldWidth), PosY: r.Intn(units.FieldHeight)}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := points[i%len(points)]
+		var nearest *Element
+		distance := 0.0
+		for _, e := range elements {
+			if d := p.DistanceTo(e.Coords); nearest == nil || d < distance {
+				nearest, distance = e, d
+			}
+		}
+	}
+}
+
+func BenchmarkGrid_WithinRadius(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	grid := NewGridWith(randomElements(r, 22)...)
+	points := make([]Point, 1024)
+	for i := range points {
+		points[i] = Point{PosX: r.Intn(units.FieldWidth), PosY: r.Intn(units.FieldHeight)}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		grid.WithinRadius(points[i%len(points)], 2*units.PlayerSize, nil)
+	}
+}
+
+func BenchmarkLinear_WithinRadius(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	elements := randomElements(r, 22)
+	points := make([]Point, 1024)
+	for i := range points {
+		points[i] = Point{PosX: r.Intn(units.FieldWidth), PosY: r.Intn(units.FieldHeight)}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p := points[i%len(points)]
+		var found []*Element
+		for _, e := range elements {
+			if p.DistanceTo(e.Coords) <= 2*units.PlayerSize {
+				found = append(found, e)
+			}
+		}
+		sortByDistance(found, p)
+	}
+}
@@ -0,0 +1,148 @@
+package physics
+
+import "math"
+
+// PassRiskTurnsMargin is the number of turns an opponent may be late to reach the ball path and still be considered
+// a risk for the pass. An opponent that reaches the path in time has risk 1, and the risk decreases linearly as the
+// opponent is late, becoming 0 when it is PassRiskTurnsMargin turns late.
+const PassRiskTurnsMargin = 3.0
+
+// InterceptionRisk is the evaluation of an opponent for a pass
+type InterceptionRisk struct {
+	// Opponent is the evaluated opponent
+	Opponent *Element
+	// Intercepts is true when the opponent reaches the ball before the receiver or in the same turn
+	Intercepts bool
+	// Turn is the turn when the opponent reaches the ball path. It is zero when it does not intercept the ball
+	Turn int
+	// Point is the ball position where the opponent reaches it. It is only set when the opponent intercepts the ball
+	Point Point
+	// TurnsLate is how many turns the opponent is late to the ball path at its best chance (zero when it intercepts)
+	TurnsLate float64
+	// Risk is the risk of this opponent intercepting the pass (0 to 1)
+	Risk float64
+}
+
+// PassEvaluation is the result of the evaluation of a pass
+type PassEvaluation struct {
+	// Velocity is the kick velocity used to evaluate the pass
+	Velocity Velocity
+	// Reaches is false when the ball stops before the receiver can reach it
+	Reaches bool
+	// ArrivalTurn is the turn when the receiver reaches the ball
+	ArrivalTurn int
+	// Risk is the risk of the pass being intercepted (0 to 1)
+	Risk float64
+	// Interceptor is the first opponent reaching the ball, nil when no opponent is able to intercept the pass
+	Interceptor *InterceptionRisk
+	// Opponents has the evaluation of each opponent in the same order they were given
+	Opponents []InterceptionRisk
+}
+
+// IsSafe returns true when the receiver reaches the ball and no opponent is able to intercept it
+func (p *PassEvaluation) IsSafe() bool {
+	return p.Reaches && p.Interceptor == nil
+}
+
+// EvaluatePass evaluates a pass from the ball position to the receiver when the ball is kicked with the speed.
+// The ball decelerates as described by BallTrajectory, and all players (receiver and opponents) are expected to run
+// towards the ball path at PlayerMaxSpeed. A player reaches the ball when the distance between their bodies is not
+// greater than the distance the player can run until that turn. An opponent reaching the ball in the same turn as the
+// receiver intercepts the pass, since the server may process the opponent orders first.
+func EvaluatePass(ball Point, speed float64, receiver Element, opponents []Element) PassEvaluation {
+	return defaultEngine.EvaluatePass(ball, speed, receiver, opponents)
+}
+
+// EvaluatePass evaluates a pass from the ball position to the receiver when the ball is kicked with the speed, see
+// EvaluatePass
+func (e Engine) EvaluatePass(ball Point, speed float64, receiver Element, opponents []Element) PassEvaluation {
+	evaluation := PassEvaluation{Opponents: make([]InterceptionRisk, len(opponents))}
+	direction, err := NewVector(ball, receiver.Coords)
+	if err != nil {
+		// the ball is already at the receiver position
+		evaluation.Reaches = true
+		evaluation.Velocity = NewZeroedVelocity(East)
+		for i := range opponents {
+			evaluation.Opponents[i] = InterceptionRisk{Opponent: &opponents[i]}
+		}
+		return evaluation
+	}
+	evaluation.Velocity = NewZeroedVelocity(*direction.Normalize())
+	evaluation.Velocity.Speed = math.Min(speed, e.Rules.BallMaxSpeed)
+
+	path := e.BallTrajectory(ball, evaluation.Velocity, 0)
+	evaluation.ArrivalTurn = e.firstReachTurn(receiver, path)
+	evaluation.Reaches = evaluation.ArrivalTurn > 0
+	lastTurn := len(path)
+	if evaluation.Reaches {
+		lastTurn = evaluation.ArrivalTurn
+	}
+
+	safeProbability := 1.0
+	for i := range opponents {
+		risk := e.EvaluateInterception(&opponents[i], ball, path[:lastTurn])
+		evaluation.Opponents[i] = risk
+		safeProbability *= 1 - risk.Risk
+		if risk.Intercepts && (evaluation.Interceptor == nil || risk.Turn < evaluation.Interceptor.Turn) {
+			evaluation.Interceptor = &evaluation.Opponents[i]
+		}
+	}
+	evaluation.Risk = 1 - safeProbability
+	if !evaluation.Reaches {
+		evaluation.Risk = 1
+	}
+	return evaluation
+}
+
+// firstReachTurn finds the first turn when the player may reach the ball in the path. It returns zero if it never does
+func (e Engine) firstReachTurn(player Element, path []Point) int {
+	touchDistance := float64(player.Size+e.Rules.BallSize) / 2
+	for i, position := range path {
+		turn := i + 1
+		if player.Coords.DistanceTo(position)-touchDistance <= e.Rules.PlayerMaxSpeed*float64(turn) {
+			return turn
+		}
+	}
+	return 0
+}
+
+// EvaluateInterception checks when the opponent running at PlayerMaxSpeed reaches the ball that leaves `ball`
+// following the path (see BallTrajectory). Only the given path is considered, so it should end when the ball is
+// expected to reach its destination (e.g. the pass receiver).
+func EvaluateInterception(opponent *Element, ball Point, path []Point) InterceptionRisk {
+	return defaultEngine.EvaluateInterception(opponent, ball, path)
+}
+
+// EvaluateInterception checks when the opponent reaches the ball path before the end of the path, see
+// EvaluateInterception
+func (e Engine) EvaluateInterception(opponent *Element, ball Point, path []Point) InterceptionRisk {
+	risk := InterceptionRisk{Opponent: opponent, TurnsLate: math.Inf(1)}
+	if len(path) == 0 {
+		return risk
+	}
+	// quick check: the opponent reach area during the whole pass (plus the risk margin) must touch the ball line
+	reach := Element{
+		Size:   2 * int(math.Ceil(e.Rules.PlayerMaxSpeed*(float64(len(path))+PassRiskTurnsMargin)+float64(opponent.Size)/2)),
+		Coords: opponent.Coords,
+	}
+	if collides, _, _ := reach.LineCollides(ball, path[len(path)-1], float64(e.Rules.BallSize)/2); !collides && ball != path[len(path)-1] {
+		return risk
+	}
+
+	touchDistance := float64(opponent.Size+e.Rules.BallSize) / 2
+	for i, position := range path {
+		turn := i + 1
+		late := (opponent.Coords.DistanceTo(position)-touchDistance)/e.Rules.PlayerMaxSpeed - float64(turn)
+		if late <= 0 {
+			risk.Intercepts = true
+			risk.Turn = turn
+			risk.Point = position
+			risk.TurnsLate = 0
+			risk.Risk = 1
+			return risk
+		}
+		risk.TurnsLate = math.Min(risk.TurnsLate, late)
+	}
+	risk.Risk = math.Max(0, 1-risk.TurnsLate/PassRiskTurnsMargin)
+	return risk
+}
@@ -0,0 +1,90 @@
+package physics
+
+import (
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEvaluatePass_Safe(t *testing.T) {
+	ball := Point{PosX: 5000, PosY: 5000}
+	receiver := Element{Size: units.PlayerSize, Coords: Point{PosX: 7000, PosY: 5000}}
+	opponents := []Element{
+		{Size: units.PlayerSize, Coords: Point{PosX: 6000, PosY: 9000}},
+		{Size: units.PlayerSize, Coords: Point{PosX: 1000, PosY: 5000}},
+	}
+
+	evaluation := EvaluatePass(ball, units.BallMaxSpeed, receiver, opponents)
+	assert.True(t, evaluation.Reaches)
+	assert.True(t, evaluation.IsSafe())
+	assert.Nil(t, evaluation.Interceptor)
+	assert.Equal(t, 4, evaluation.ArrivalTurn)
+	assert.Equal(t, 0.0, evaluation.Risk)
+	assert.Len(t, evaluation.Opponents, 2)
+	assert.Equal(t, &opponents[0], evaluation.Opponents[0].Opponent)
+	assert.False(t, evaluation.Opponents[0].Intercepts)
+}
+
+func TestEvaluatePass_Intercepted(t *testing.T) {
+	ball := Point{PosX: 5000, PosY: 5000}
+	receiver := Element{Size: units.PlayerSize, Coords: Point{PosX: 9000, PosY: 5000}}
+	opponents := []Element{
+		{Size: units.PlayerSize, Coords: Point{PosX: 8000, PosY: 5600}},
+		{Size: units.PlayerSize, Coords: Point{PosX: 6000, PosY: 5300}},
+	}
+
+	evaluation := EvaluatePass(ball, units.BallMaxSpeed, receiver, opponents)
+	assert.True(t, evaluation.Reaches)
+	assert.False(t, evaluation.IsSafe())
+	assert.Equal(t, 1.0, evaluation.Risk)
+	if assert.NotNil(t, evaluation.Interceptor) {
+		assert.Equal(t, &opponents[1], evaluation.Interceptor.Opponent)
+		assert.Equal(t, 2, evaluation.Interceptor.Turn)
+		assert.Equal(t, Point{PosX: 5790, PosY: 5000}, evaluation.Interceptor.Point)
+	}
+	assert.True(t, evaluation.Opponents[0].Intercepts)
+}
+
+func TestEvaluatePass_RiskyButNotIntercepted(t *testing.T) {
+	ball := Point{PosX: 5000, PosY: 5000}
+	receiver := Element{Size: units.PlayerSize, Coords: Point{PosX: 6200, PosY: 5000}}
+	opponents := []Element{
+		{Size: units.PlayerSize, Coords: Point{PosX: 6000, PosY: 5650}},
+	}
+
+	evaluation := EvaluatePass(ball, units.BallMaxSpeed, receiver, opponents)
+	assert.True(t, evaluation.IsSafe())
+	assert.True(t, evaluation.Risk > 0 && evaluation.Risk < 1, "risk %f", evaluation.Risk)
+	assert.True(t, evaluation.Opponents[0].TurnsLate > 0)
+}
+
+func TestEvaluatePass_Tie(t *testing.T) {
+	ball := Point{PosX: 5000, PosY: 5000}
+	receiver := Element{Size: units.PlayerSize, Coords: Point{PosX: 7000, PosY: 5000}}
+	// the opponent is behind the receiver, but reaches the ball in the same turn
+	opponents := []Element{{Size: units.PlayerSize, Coords: Point{PosX: 7100, PosY: 5000}}}
+
+	evaluation := EvaluatePass(ball, units.BallMaxSpeed, receiver, opponents)
+	assert.True(t, evaluation.Reaches)
+	assert.False(t, evaluation.IsSafe())
+	assert.Equal(t, 1.0, evaluation.Risk)
+	if assert.NotNil(t, evaluation.Interceptor) {
+		assert.Equal(t, evaluation.ArrivalTurn, evaluation.Interceptor.Turn)
+	}
+
+	// one turn later, the opponent is only a risk
+	opponents = []Element{{Size: units.PlayerSize, Coords: Point{PosX: 7500, PosY: 5000}}}
+	evaluation = EvaluatePass(ball, units.BallMaxSpeed, receiver, opponents)
+	assert.True(t, evaluation.IsSafe())
+	assert.True(t, evaluation.Risk > 0, "risk %f", evaluation.Risk)
+}
+
+func TestEvaluatePass_TooSlow(t *testing.T) {
+	ball := Point{PosX: 1000, PosY: 5000}
+	receiver := Element{Size: units.PlayerSize, Coords: Point{PosX: 9000, PosY: 5000}}
+
+	evaluation := EvaluatePass(ball, 50, receiver, nil)
+	assert.False(t, evaluation.Reaches)
+	assert.False(t, evaluation.IsSafe())
+	assert.Equal(t, 1.0, evaluation.Risk)
+}
@@ -0,0 +1,246 @@
+package physics
+
+import (
+	"container/heap"
+	"errors"
+	"github.com/lugobots/arena/units"
+	"math"
+)
+
+// ErrNoPath is returned by the path planner when the player cannot move towards the goal at all
+var ErrNoPath = errors.New("there is no path to the goal")
+
+// PathPlanner finds paths for a player avoiding obstacles. The obstacles are expected to keep their velocities, so
+// the planner avoids the positions where they will be when the player passes by.
+type PathPlanner struct {
+	// CellSize is the resolution of the search lattice
+	CellSize int
+	// Margin is the extra distance kept between the player body and the obstacles bodies
+	Margin float64
+	// Speed is the player speed used to predict when the player will pass by each point
+	Speed float64
+	// MaxNodes limits the number of nodes expanded by the search
+	MaxNodes int
+	// Rules define the field size and the player size
+	Rules units.Rules
+}
+
+// NewPathPlanner creates a path planner for players running at the max speed
+func NewPathPlanner() *PathPlanner {
+	return NewPathPlannerFromRules(units.DefaultRules())
+}
+
+// NewPathPlannerFromRules creates a path planner for players running at the max speed defined by the rules
+func NewPathPlannerFromRules(rules units.Rules) *PathPlanner {
+	return &PathPlanner{
+		CellSize: rules.PlayerSize / 2,
+		Margin:   float64(rules.PlayerSize) / 4,
+		Speed:    rules.PlayerMaxSpeed,
+		MaxNodes: 20000,
+		Rules:    rules,
+	}
+}
+
+// Plan finds the waypoints that a player in `start` should follow to reach the `goal` without colliding with the
+// obstacles. The start point is not included in the waypoints, and the last waypoint is the goal.
+// When the goal cannot be reached (e.g. it is inside an obstacle) the path ends at the closest point the player may
+// reach. ErrNoPath is returned when the player is not able to move at all.
+func (pp *PathPlanner) Plan(start, goal Point, obstacles []Element) ([]Point, error) {
+	engine := NewEngine(pp.Rules)
+	goal = engine.ClampToField(goal, pp.Rules.PlayerSize)
+	if start == goal {
+		return []Point{goal}, nil
+	}
+	clearances := pp.clearances(start, obstacles)
+	if pp.isSegmentFree(start, goal, 0, obstacles, clearances) {
+		return []Point{goal}, nil
+	}
+
+	cellSize := float64(pp.CellSize)
+	type key struct{ i, j int }
+	nodes := map[key]*planNode{}
+	open := &planQueue{}
+	root := &planNode{point: start, heuristic: start.DistanceTo(goal)}
+	nodes[key{}] = root
+	heap.Push(open, root)
+
+	closest := root
+	var reached *planNode
+	for expanded := 0; open.Len() > 0 && expanded < pp.MaxNodes; expanded++ {
+		current := heap.Pop(open).(*planNode)
+		current.closed = true
+		if current.heuristic < closest.heuristic {
+			closest = current
+		}
+		if current.heuristic <= cellSize*math.Sqrt2 && pp.isSegmentFree(current.point, goal, current.cost, obstacles, clearances) {
+			reached = &planNode{point: goal, parent: current}
+			break
+		}
+		for _, offset := range planNeighbours {
+			k := key{current.i + offset[0], current.j + offset[1]}
+			point := Point{PosX: start.PosX + k.i*pp.CellSize, PosY: start.PosY + k.j*pp.CellSize}
+			if engine.ClampToField(point, pp.Rules.PlayerSize) != point {
+				continue
+			}
+			cost := current.cost + current.point.DistanceTo(point)
+			if pp.isBlocked(point, cost/pp.Speed, obstacles, clearances) {
+				continue
+			}
+			node, ok := nodes[k]
+			if ok && (node.closed || node.cost <= cost) {
+				continue
+			}
+			if !ok {
+				node = &planNode{i: k.i, j: k.j, point: point, heuristic: point.DistanceTo(goal), index: -1}
+				nodes[k] = node
+			}
+			node.cost = cost
+			node.parent = current
+			if node.index >= 0 {
+				heap.Fix(open, node.index)
+			} else {
+				heap.Push(open, node)
+			}
+		}
+	}
+
+	if reached == nil {
+		if closest == root {
+			return nil, ErrNoPath
+		}
+		reached = closest
+	}
+	var path []Point
+	for node := reached; node != nil; node = node.parent {
+		path = append([]Point{node.point}, path...)
+	}
+	return pp.smooth(path, obstacles, clearances), nil
+}
+
+// NextVelocity returns the velocity that the player in `start` should use in the next turn to follow the path to
+// the `goal`. The speed is never greater than the planner speed, and the player stops when it is at the goal.
+func (pp *PathPlanner) NextVelocity(start, goal Point, obstacles []Element) (Velocity, error) {
+	path, err := pp.Plan(start, goal, obstacles)
+	if err != nil {
+		return NewZeroedVelocity(East), err
+	}
+	direction, err := NewVector(start, path[0])
+	if err != nil {
+		return NewZeroedVelocity(East), nil
+	}
+	velocity := NewZeroedVelocity(*direction.Normalize())
+	velocity.Speed = math.Min(pp.Speed, start.DistanceTo(path[0]))
+	return velocity, nil
+}
+
+// clearances finds the min distance between the player center and each obstacle center. When the player is already
+// closer than that to an obstacle, the clearance is reduced to let the player move away from it.
+func (pp *PathPlanner) clearances(start Point, obstacles []Element) []float64 {
+	clearances := make([]float64, len(obstacles))
+	for i, obstacle := range obstacles {
+		clearance := float64(pp.Rules.PlayerSize+obstacle.Size)/2 + pp.Margin
+		if current := start.DistanceTo(obstacle.Coords); current <= clearance {
+			clearance = current - 1
+		}
+		clearances[i] = clearance
+	}
+	return clearances
+}
+
+// isBlocked checks if the player would collide with any obstacle in the point after `turns` turns
+func (pp *PathPlanner) isBlocked(p Point, turns float64, obstacles []Element, clearances []float64) bool {
+	for i, obstacle := range obstacles {
+		if p.DistanceTo(PredictPosition(obstacle, turns)) < clearances[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// isSegmentFree checks if the player can go straight from `a` to `b`, having already travelled `cost` units
+func (pp *PathPlanner) isSegmentFree(a, b Point, cost float64, obstacles []Element, clearances []float64) bool {
+	startTurn := cost / pp.Speed
+	endTurn := (cost + a.DistanceTo(b)) / pp.Speed
+	for i, obstacle := range obstacles {
+		for _, turns := range []float64{startTurn, (startTurn + endTurn) / 2, endTurn} {
+			position := PredictPosition(obstacle, turns)
+			if position.DistanceToSegment(a, b) < clearances[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// smooth removes the waypoints that can be skipped by going straight to a later waypoint
+func (pp *PathPlanner) smooth(path []Point, obstacles []Element, clearances []float64) []Point {
+	smoothed := []Point{}
+	anchor := path[0]
+	cost := 0.0
+	for i := 1; i < len(path); {
+		next := i
+		for j := len(path) - 1; j > i; j-- {
+			if pp.isSegmentFree(anchor, path[j], cost, obstacles, clearances) {
+				next = j
+				break
+			}
+		}
+		smoothed = append(smoothed, path[next])
+		cost += anchor.DistanceTo(path[next])
+		anchor = path[next]
+		i = next + 1
+	}
+	return smoothed
+}
+
+// PredictPosition finds where the element will be after `turns` turns keeping its velocity
+func PredictPosition(e Element, turns float64) Point {
+	if e.Velocity.Direction == nil || e.Velocity.Speed == 0 || turns <= 0 {
+		return e.Coords
+	}
+	moved := e.Velocity.Copy()
+	moved.Speed *= turns
+	return moved.Target(e.Coords)
+}
+
+var planNeighbours = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+
+type planNode struct {
+	i, j      int
+	point     Point
+	cost      float64
+	heuristic float64
+	parent    *planNode
+	// index is the node position in the queue, -1 when it is not queued
+	index  int
+	closed bool
+}
+
+// planQueue is a priority queue of nodes ordered by their estimated total cost
+type planQueue []*planNode
+
+func (q planQueue) Len() int { return len(q) }
+
+func (q planQueue) Less(i, j int) bool {
+	return q[i].cost+q[i].heuristic < q[j].cost+q[j].heuristic
+}
+
+func (q planQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *planQueue) Push(x interface{}) {
+	node := x.(*planNode)
+	node.index = len(*q)
+	*q = append(*q, node)
+}
+
+func (q *planQueue) Pop() interface{} {
+	old := *q
+	node := old[len(old)-1]
+	node.index = -1
+	*q = old[:len(old)-1]
+	return node
+}
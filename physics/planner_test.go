@@ -0,0 +1,136 @@
+package physics
+
+import (
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// assertPathIsFree checks that a player following the path at max speed never collides with the obstacles
+func assertPathIsFree(t *testing.T, start Point, path []Point, obstacles []Element, title string) {
+	position := start
+	turn := 0.0
+	for _, waypoint := range path {
+		for position != waypoint {
+			direction, _ := NewVector(position, waypoint)
+			step := NewZeroedVelocity(*direction.Normalize())
+			step.Speed = position.DistanceTo(waypoint)
+			if step.Speed > units.PlayerMaxSpeed {
+				step.Speed = units.PlayerMaxSpeed
+			}
+			position = step.Target(position)
+			turn++
+			for _, obstacle := range obstacles {
+				minDistance := float64(units.PlayerSize+obstacle.Size) / 2
+				obstaclePosition := PredictPosition(obstacle, turn)
+				if !assert.True(t, position.DistanceTo(obstaclePosition) >= minDistance, "%s: collision at turn %.0f in %v", title, turn, position) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestPathPlanner_StraightLine(t *testing.T) {
+	planner := NewPathPlanner()
+	start := Point{PosX: 1000, PosY: 1000}
+	goal := Point{PosX: 3000, PosY: 1000}
+
+	path, err := planner.Plan(start, goal, []Element{{Size: units.PlayerSize, Coords: Point{PosX: 2000, PosY: 3000}}})
+	assert.Nil(t, err)
+	assert.Equal(t, []Point{goal}, path)
+
+	velocity, err := planner.NextVelocity(start, goal, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, units.PlayerMaxSpeed, velocity.Speed)
+	assert.Equal(t, Point{PosX: 1100, PosY: 1000}, velocity.Target(start))
+}
+
+func TestPathPlanner_AvoidsObstacle(t *testing.T) {
+	planner := NewPathPlanner()
+	start := Point{PosX: 1000, PosY: 5000}
+	goal := Point{PosX: 3000, PosY: 5000}
+	obstacles := []Element{{Size: units.PlayerSize, Coords: Point{PosX: 2000, PosY: 5000}}}
+
+	path, err := planner.Plan(start, goal, obstacles)
+	assert.Nil(t, err)
+	assert.True(t, len(path) > 1)
+	assert.Equal(t, goal, path[len(path)-1])
+	assertPathIsFree(t, start, path, obstacles, "single obstacle")
+
+	velocity, err := planner.NextVelocity(start, goal, obstacles)
+	assert.Nil(t, err)
+	assert.NotEqual(t, 0.0, velocity.Direction.GetY())
+}
+
+func TestPathPlanner_MovingObstacle(t *testing.T) {
+	planner := NewPathPlanner()
+	start := Point{PosX: 1000, PosY: 5000}
+	goal := Point{PosX: 4000, PosY: 5000}
+	crossing := Element{Size: units.PlayerSize, Coords: Point{PosX: 2500, PosY: 6500}}
+	crossing.Velocity = NewVelocityTo(Point{}, Point{PosY: -1}, units.PlayerMaxSpeed)
+	obstacles := []Element{crossing}
+
+	path, err := planner.Plan(start, goal, obstacles)
+	assert.Nil(t, err)
+	assert.Equal(t, goal, path[len(path)-1])
+	assertPathIsFree(t, start, path, obstacles, "moving obstacle")
+}
+
+func TestPathPlanner_CrowdedGoalArea(t *testing.T) {
+	planner := NewPathPlanner()
+	midY := units.FieldHeight / 2
+	start := Point{PosX: units.FieldWidth - 3000, PosY: midY}
+	goal := Point{PosX: units.FieldWidth - 900, PosY: midY}
+	obstacles := []Element{
+		{Size: units.PlayerSize, Coords: Point{PosX: units.FieldWidth - 300, PosY: midY}},
+		{Size: units.PlayerSize, Coords: Point{PosX: units.FieldWidth - 1500, PosY: midY}},
+		{Size: units.PlayerSize, Coords: Point{PosX: units.FieldWidth - 1500, PosY: midY + 500}},
+		{Size: units.PlayerSize, Coords: Point{PosX: units.FieldWidth - 1500, PosY: midY - 500}},
+		{Size: units.PlayerSize, Coords: Point{PosX: units.FieldWidth - 1000, PosY: midY + 900}},
+		{Size: units.PlayerSize, Coords: Point{PosX: units.FieldWidth - 2000, PosY: midY - 900}},
+	}
+
+	path, err := planner.Plan(start, goal, obstacles)
+	assert.Nil(t, err)
+	assert.Equal(t, goal, path[len(path)-1])
+	assertPathIsFree(t, start, path, obstacles, "crowded goal area")
+	for _, waypoint := range path {
+		assert.Equal(t, ClampToField(waypoint, units.PlayerSize), waypoint)
+	}
+}
+
+func TestPathPlanner_BlockedGoal(t *testing.T) {
+	planner := NewPathPlanner()
+	start := Point{PosX: 1000, PosY: 5000}
+	goal := Point{PosX: 3000, PosY: 5000}
+	obstacles := []Element{{Size: units.PlayerSize, Coords: Point{PosX: 3000, PosY: 5000}}}
+
+	path, err := planner.Plan(start, goal, obstacles)
+	assert.Nil(t, err)
+	last := path[len(path)-1]
+	assert.True(t, last.DistanceTo(goal) < float64(2*units.PlayerSize))
+	assertPathIsFree(t, start, path, obstacles, "blocked goal")
+}
+
+func TestPathPlanner_NoPath(t *testing.T) {
+	planner := NewPathPlanner()
+	start := Point{PosX: 300, PosY: 300}
+	obstacles := []Element{
+		{Size: units.PlayerSize, Coords: Point{PosX: 700, PosY: 300}},
+		{Size: units.PlayerSize, Coords: Point{PosX: 300, PosY: 700}},
+		{Size: units.PlayerSize, Coords: Point{PosX: 700, PosY: 700}},
+	}
+	_, err := planner.Plan(start, Point{PosX: 5000, PosY: 5000}, obstacles)
+	assert.Equal(t, ErrNoPath, err)
+}
+
+func TestPredictPosition(t *testing.T) {
+	moving := Element{Coords: Point{PosX: 1000, PosY: 1000}, Velocity: NewZeroedVelocity(East)}
+	moving.Velocity.Speed = 100
+	assert.Equal(t, Point{PosX: 1250, PosY: 1000}, PredictPosition(moving, 2.5))
+	assert.Equal(t, moving.Coords, PredictPosition(moving, 0))
+
+	still := Element{Coords: Point{PosX: 1000, PosY: 1000}}
+	assert.Equal(t, still.Coords, PredictPosition(still, 10))
+}
@@ -29,6 +29,19 @@ func (p *Point) MiddlePointTo(target Point) Point {
 	}
 }
 
+// DistanceToSegment finds the shortest distance of this point to the segment between the points `a` and `b`
+func (p *Point) DistanceToSegment(a, b Point) float64 {
+	abX := float64(b.PosX - a.PosX)
+	abY := float64(b.PosY - a.PosY)
+	lengthSq := abX*abX + abY*abY
+	if lengthSq == 0 {
+		return p.DistanceTo(a)
+	}
+	t := (float64(p.PosX-a.PosX)*abX + float64(p.PosY-a.PosY)*abY) / lengthSq
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(float64(a.PosX)+t*abX-float64(p.PosX), float64(a.PosY)+t*abY-float64(p.PosY))
+}
+
 // String returns the string representation of a point
 func (p *Point) String() string {
 	return fmt.Sprintf("{%d, %d}", p.PosX, p.PosY)
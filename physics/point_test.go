@@ -89,3 +89,23 @@ func TestDeterminant_DiagonalNotTouching(t *testing.T) {
 	assert.Equal(t, Point{PosX: 15, PosY: 15}, p)
 	assert.False(t, touch)
 }
+
+func TestPoint_DistanceToSegment(t *testing.T) {
+	a := Point{PosX: 0, PosY: 0}
+	b := Point{PosX: 10, PosY: 0}
+
+	p := Point{PosX: 5, PosY: 5}
+	assert.Equal(t, 5.0, p.DistanceToSegment(a, b))
+
+	p = Point{PosX: -3, PosY: 4}
+	assert.Equal(t, 5.0, p.DistanceToSegment(a, b))
+
+	p = Point{PosX: 13, PosY: -4}
+	assert.Equal(t, 5.0, p.DistanceToSegment(a, b))
+
+	p = Point{PosX: 7, PosY: 0}
+	assert.Equal(t, 0.0, p.DistanceToSegment(a, b))
+
+	p = Point{PosX: 3, PosY: 4}
+	assert.Equal(t, 5.0, p.DistanceToSegment(a, a))
+}
@@ -0,0 +1,50 @@
+package physics
+
+// MaxTrajectoryTurns is the limit of turns of a prediction without limit. The validated rules always stop the ball,
+// but an Engine may hold rules that were never validated (e.g. the zero Engine has no deceleration), and a huge speed
+// would take too many turns to stop anyway.
+const MaxTrajectoryTurns = 10000
+
+// BallTrajectory predicts the ball positions in the next turns when it moves from `from` with the velocity.
+// Every turn the ball moves by its speed and then loses BallDeceleration speed units, so the prediction ends when the
+// ball speed is at or below BallMinSpeed or after `maxTurns` turns (zero means MaxTrajectoryTurns). The first position is the one
+// after the first turn, so the position at the turn `t` is the index `t-1`.
+// The field borders are not considered.
+func BallTrajectory(from Point, velocity Velocity, maxTurns int) []Point {
+	return defaultEngine.BallTrajectory(from, velocity, maxTurns)
+}
+
+// BallTrajectory predicts the ball positions in the next turns, see BallTrajectory
+func (e Engine) BallTrajectory(from Point, velocity Velocity, maxTurns int) []Point {
+	var path []Point
+	if velocity.Direction == nil {
+		return path
+	}
+	if maxTurns <= 0 || maxTurns > MaxTrajectoryTurns {
+		maxTurns = MaxTrajectoryTurns
+	}
+	current := velocity.Copy()
+	position := from
+	for current.Speed > e.Rules.BallMinSpeed && len(path) < maxTurns {
+		position = current.Target(position)
+		path = append(path, position)
+		current.Speed -= e.Rules.BallDeceleration
+	}
+	return path
+}
+
+// BallTravelDistance returns the distance the ball moves before stopping when kicked with the speed
+func BallTravelDistance(speed float64) float64 {
+	return defaultEngine.BallTravelDistance(speed)
+}
+
+// BallTravelDistance returns the distance the ball moves before stopping when kicked with the speed. A ball that never
+// stops is only followed for MaxTrajectoryTurns turns.
+func (e Engine) BallTravelDistance(speed float64) float64 {
+	distance := 0.0
+	for turn := 0; speed > e.Rules.BallMinSpeed && turn < MaxTrajectoryTurns; turn++ {
+		distance += speed
+		speed -= e.Rules.BallDeceleration
+	}
+	return distance
+}
@@ -0,0 +1,38 @@
+package physics
+
+import (
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBallTrajectory(t *testing.T) {
+	path := BallTrajectory(Point{}, NewVelocityTo(Point{}, Point{PosX: 1}, 35), 0)
+	assert.Equal(t, []Point{{PosX: 35}, {PosX: 60}, {PosX: 75}, {PosX: 80}}, path)
+
+	path = BallTrajectory(Point{}, NewVelocityTo(Point{}, Point{PosX: 1}, 35), 2)
+	assert.Equal(t, []Point{{PosX: 35}, {PosX: 60}}, path)
+
+	// the ball stops when its speed reaches BallMinSpeed
+	path = BallTrajectory(Point{}, NewVelocityTo(Point{}, Point{PosX: 1}, units.BallMinSpeed+units.BallDeceleration), 0)
+	assert.Equal(t, []Point{{PosX: units.BallMinSpeed + units.BallDeceleration}}, path)
+	assert.Empty(t, BallTrajectory(Point{}, NewVelocityTo(Point{}, Point{PosX: 1}, units.BallMinSpeed), 0))
+	assert.Empty(t, BallTrajectory(Point{}, Velocity{}, 0))
+}
+
+func TestBallTravelDistance(t *testing.T) {
+	assert.Equal(t, 80.0, BallTravelDistance(35))
+	assert.Equal(t, 0.0, BallTravelDistance(0))
+	assert.Equal(t, 0.0, BallTravelDistance(units.BallMinSpeed))
+	path := BallTrajectory(Point{}, NewVelocityTo(Point{}, Point{PosX: 1}, units.BallMaxSpeed), 0)
+	assert.Equal(t, BallTravelDistance(units.BallMaxSpeed), float64(path[len(path)-1].PosX))
+}
+
+func TestEngine_BallTrajectory_NoDeceleration(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.BallDeceleration = 0
+	engine := NewEngine(rules)
+	assert.Len(t, engine.BallTrajectory(Point{}, NewVelocityTo(Point{}, Point{PosX: 1}, 10), 0), MaxTrajectoryTurns)
+	assert.Len(t, engine.BallTrajectory(Point{}, NewVelocityTo(Point{}, Point{PosX: 1}, 10), 5), 5)
+	assert.Equal(t, 10.0*MaxTrajectoryTurns, engine.BallTravelDistance(10))
+}
@@ -19,6 +19,18 @@ func NewZeroedVelocity(direction Vector) Velocity {
 	return s
 }
 
+// NewVelocityTo creates a velocity pointing from the point `from` to the point `to` with the speed. The velocity has
+// no direction nor speed when the points are the same.
+func NewVelocityTo(from, to Point, speed float64) Velocity {
+	direction, err := NewVector(from, to)
+	if err != nil {
+		return Velocity{}
+	}
+	velocity := NewZeroedVelocity(*direction.Normalize())
+	velocity.Speed = speed
+	return velocity
+}
+
 // Copy copies the object
 func (v *Velocity) Copy() Velocity {
 	copyS := NewZeroedVelocity(*v.Direction.Copy())
@@ -37,3 +37,11 @@ func TestVector_Add(t *testing.T) {
 	assert.Equal(t, float64(100), velD.Speed)
 
 }
+
+func TestNewVelocityTo(t *testing.T) {
+	velocity := NewVelocityTo(Point{PosX: 100, PosY: 100}, Point{PosX: 400, PosY: 500}, 50)
+	assert.Equal(t, 50.0, velocity.Speed)
+	assert.Equal(t, Point{PosX: 130, PosY: 140}, velocity.Target(Point{PosX: 100, PosY: 100}))
+
+	assert.Equal(t, Velocity{}, NewVelocityTo(Point{PosX: 100}, Point{PosX: 100}, 50))
+}
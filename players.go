@@ -0,0 +1,222 @@
+package arena
+
+import (
+	"fmt"
+	"github.com/lugobots/arena/units"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MinPlayerNumber is the lowest number a player may have
+const MinPlayerNumber = 1
+
+// MaxPlayerNumber is the highest number a player may have, so it is also the max number of players in a team
+const MaxPlayerNumber = 11
+
+// ErrEmptyPlayerNumber is returned (wrapped, see errors.Cause) when a player number is empty
+var ErrEmptyPlayerNumber = errors.New("the player number is empty")
+
+// ErrPlayerNumberNotNumeric is returned (wrapped, see errors.Cause) when a player number is not an integer
+var ErrPlayerNumberNotNumeric = errors.New("the player number is not an integer")
+
+// ErrPlayerNumberOutOfRange is returned (wrapped, see errors.Cause) when a player number is not between
+// MinPlayerNumber and MaxPlayerNumber
+var ErrPlayerNumberOutOfRange = errors.New("the player number is out of range")
+
+// ErrInvalidTeamPlace is returned (wrapped, see errors.Cause) when a team place is neither HomeTeam nor AwayTeam
+var ErrInvalidTeamPlace = errors.New("the team place is invalid")
+
+// ParsePlayerNumber reads a player number. Spaces and leading zeros are removed, so "07" is the player number "7".
+func ParsePlayerNumber(s string) (PlayerNumber, error) {
+	value := strings.TrimSpace(s)
+	if value == "" {
+		return "", ErrEmptyPlayerNumber
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return "", errors.Wrapf(ErrPlayerNumberNotNumeric, "number %q", s)
+	}
+	if n < MinPlayerNumber || n > MaxPlayerNumber {
+		return "", errors.Wrapf(ErrPlayerNumberOutOfRange, "number %d must be between %d and %d", n, MinPlayerNumber, MaxPlayerNumber)
+	}
+	return PlayerNumber(strconv.Itoa(n)), nil
+}
+
+// PlayerNumbers lists all valid player numbers in ascending order
+func PlayerNumbers() []PlayerNumber {
+	numbers := make([]PlayerNumber, 0, MaxPlayerNumber-MinPlayerNumber+1)
+	for n := MinPlayerNumber; n <= MaxPlayerNumber; n++ {
+		numbers = append(numbers, PlayerNumber(strconv.Itoa(n)))
+	}
+	return numbers
+}
+
+// Validate checks if the number is in its canonical form (see ParsePlayerNumber) and in the valid range
+func (n PlayerNumber) Validate() error {
+	parsed, err := ParsePlayerNumber(string(n))
+	if err != nil {
+		return err
+	}
+	if parsed != n {
+		return errors.Wrapf(ErrPlayerNumberNotNumeric, "number %q should be written as %q", string(n), string(parsed))
+	}
+	return nil
+}
+
+// Int returns the number as an integer
+func (n PlayerNumber) Int() (int, error) {
+	if err := n.Validate(); err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(n))
+}
+
+// IsGoalkeeper tells if the number is the goalkeeper number
+func (n PlayerNumber) IsGoalkeeper() bool {
+	return n == GoalkeeperNumber
+}
+
+// Role returns the role of the player number in the default roles
+func (n PlayerNumber) Role() Role {
+	return DefaultRoles.Role(n)
+}
+
+// Role is the position group of a player in the team
+type Role string
+
+const (
+	// GoalkeeperRole is the player defending the goal, the only one allowed to jump
+	GoalkeeperRole Role = "goalkeeper"
+	// DefenderRole are the players closer to the team goal
+	DefenderRole Role = "defender"
+	// MidfielderRole are the players between the defenders and the forwards
+	MidfielderRole Role = "midfielder"
+	// ForwardRole are the players closer to the opponent goal
+	ForwardRole Role = "forward"
+	// UnknownRole is the role of numbers that are not in a role map
+	UnknownRole Role = ""
+)
+
+// RoleMap maps each player number to its role. Teams may use their own maps, since only the goalkeeper number is
+// defined by the game rules.
+type RoleMap map[PlayerNumber]Role
+
+// DefaultRoles is the role map of the classic numbering for a 4-4-2 formation
+var DefaultRoles = RoleMap{
+	"1":  GoalkeeperRole,
+	"2":  DefenderRole,
+	"3":  DefenderRole,
+	"4":  DefenderRole,
+	"5":  DefenderRole,
+	"6":  MidfielderRole,
+	"7":  MidfielderRole,
+	"8":  MidfielderRole,
+	"9":  MidfielderRole,
+	"10": ForwardRole,
+	"11": ForwardRole,
+}
+
+// Role returns the role of the player number, or UnknownRole when the number is not in the map
+func (m RoleMap) Role(number PlayerNumber) Role {
+	if role, ok := m[number]; ok {
+		return role
+	}
+	return UnknownRole
+}
+
+// Numbers lists the player numbers of the role in ascending order
+func (m RoleMap) Numbers(role Role) []PlayerNumber {
+	var numbers []PlayerNumber
+	for _, number := range PlayerNumbers() {
+		if m[number] == role {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
+
+// Validate checks if the map only has valid numbers and if the goalkeeper number is the only goalkeeper
+// The numbers are checked in alphabetical order, so the same map always reports the same error.
+func (m RoleMap) Validate() error {
+	numbers := make([]string, 0, len(m))
+	for number := range m {
+		numbers = append(numbers, string(number))
+	}
+	sort.Strings(numbers)
+	for _, n := range numbers {
+		number := PlayerNumber(n)
+		if err := number.Validate(); err != nil {
+			return err
+		}
+		if role := m[number]; (role == GoalkeeperRole) != number.IsGoalkeeper() {
+			return fmt.Errorf("only the player number %s may have the role %s", GoalkeeperNumber, GoalkeeperRole)
+		}
+	}
+	return nil
+}
+
+// Validate checks if the team place is HomeTeam or AwayTeam
+func (p TeamPlace) Validate() error {
+	if p != HomeTeam && p != AwayTeam {
+		return errors.Wrapf(ErrInvalidTeamPlace, "place %q", string(p))
+	}
+	return nil
+}
+
+// Opponent returns the place of the other team
+func (p TeamPlace) Opponent() TeamPlace {
+	if p == HomeTeam {
+		return AwayTeam
+	}
+	return HomeTeam
+}
+
+// Goal returns the goal defended by the team. Invalid places get the home team goal, as Opponent does.
+func (p TeamPlace) Goal() Goal {
+	if p == AwayTeam {
+		return AwayTeamGoal
+	}
+	return HomeTeamGoal
+}
+
+// AttackGoal returns the goal the team should score on
+func (p TeamPlace) AttackGoal() Goal {
+	return p.Opponent().Goal()
+}
+
+// Validate checks if the specifications may be sent to the game server: the number must be valid, the protocol
+// version, when there is one, must have a rule profile (see LookupProfile), and the initial coords must be inside the
+// field of that profile. Specifications without a version are checked against the default rules (see
+// ValidateProfile). The typed errors of the number and of the version are kept (see errors.Cause).
+func (s PlayerSpecifications) Validate() error {
+	if s.ProtocolVersion != "" {
+		return s.ValidateProfile()
+	}
+	if err := s.Number.Validate(); err != nil {
+		return errors.Wrap(err, "invalid player specifications")
+	}
+	return s.validateCoords(units.DefaultRules())
+}
+
+// ValidateProfile checks the specifications as Validate does, but also requires a rule profile registered for the
+// protocol version even when there is no version. The typed errors of the number and of the version are kept (see
+// errors.Cause).
+func (s PlayerSpecifications) ValidateProfile() error {
+	if err := s.Number.Validate(); err != nil {
+		return errors.Wrap(err, "invalid player specifications")
+	}
+	profile, err := LookupProfile(s.ProtocolVersion)
+	if err != nil {
+		return errors.Wrap(err, "invalid player specifications")
+	}
+	return s.validateCoords(profile.Rules)
+}
+
+func (s PlayerSpecifications) validateCoords(rules units.Rules) error {
+	if !NewFieldFromRules(rules).Contains(s.InitialCoords) {
+		return fmt.Errorf("invalid player specifications: the initial coords (%d, %d) are out of the field", s.InitialCoords.PosX, s.InitialCoords.PosY)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package arena
+
+import (
+	"github.com/lugobots/arena/physics"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParsePlayerNumber(t *testing.T) {
+	number, err := ParsePlayerNumber(" 07 ")
+	assert.Nil(t, err)
+	assert.Equal(t, PlayerNumber("7"), number)
+
+	number, err = ParsePlayerNumber("11")
+	assert.Nil(t, err)
+	assert.Equal(t, PlayerNumber("11"), number)
+
+	_, err = ParsePlayerNumber("")
+	assert.Equal(t, ErrEmptyPlayerNumber, errors.Cause(err))
+
+	_, err = ParsePlayerNumber("ten")
+	assert.Equal(t, ErrPlayerNumberNotNumeric, errors.Cause(err))
+
+	_, err = ParsePlayerNumber("0")
+	assert.Equal(t, ErrPlayerNumberOutOfRange, errors.Cause(err))
+
+	_, err = ParsePlayerNumber("12")
+	assert.Equal(t, ErrPlayerNumberOutOfRange, errors.Cause(err))
+}
+
+func TestPlayerNumber_Validate(t *testing.T) {
+	assert.Nil(t, GoalkeeperNumber.Validate())
+	assert.Equal(t, ErrPlayerNumberNotNumeric, errors.Cause(PlayerNumber("07").Validate()))
+	assert.Equal(t, ErrPlayerNumberOutOfRange, errors.Cause(PlayerNumber("-1").Validate()))
+
+	n, err := PlayerNumber("10").Int()
+	assert.Nil(t, err)
+	assert.Equal(t, 10, n)
+
+	assert.Len(t, PlayerNumbers(), MaxPlayerNumber)
+	assert.Equal(t, PlayerNumber("1"), PlayerNumbers()[0])
+	assert.Equal(t, PlayerNumber("11"), PlayerNumbers()[10])
+}
+
+func TestRoleMap(t *testing.T) {
+	assert.Nil(t, DefaultRoles.Validate())
+	assert.Equal(t, GoalkeeperRole, GoalkeeperNumber.Role())
+	assert.Equal(t, ForwardRole, PlayerNumber("10").Role())
+	assert.Equal(t, []PlayerNumber{"2", "3", "4", "5"}, DefaultRoles.Numbers(DefenderRole))
+
+	threeFiveTwo := RoleMap{"1": GoalkeeperRole, "2": DefenderRole, "3": DefenderRole, "4": DefenderRole, "5": MidfielderRole}
+	assert.Nil(t, threeFiveTwo.Validate())
+	assert.Equal(t, MidfielderRole, threeFiveTwo.Role("5"))
+	assert.Equal(t, UnknownRole, threeFiveTwo.Role("9"))
+
+	assert.NotNil(t, RoleMap{"5": GoalkeeperRole}.Validate())
+	assert.NotNil(t, RoleMap{"1": DefenderRole}.Validate())
+	assert.Equal(t, ErrPlayerNumberOutOfRange, errors.Cause(RoleMap{"12": ForwardRole}.Validate()))
+	// the first invalid number in alphabetical order is always the reported one
+	for i := 0; i < 10; i++ {
+		err := RoleMap{"5": GoalkeeperRole, "12": ForwardRole, "07": DefenderRole}.Validate()
+		assert.Equal(t, ErrPlayerNumberNotNumeric, errors.Cause(err))
+	}
+}
+
+func TestTeamPlace(t *testing.T) {
+	assert.Equal(t, AwayTeam, HomeTeam.Opponent())
+	assert.Equal(t, HomeTeam, AwayTeam.Opponent())
+
+	assert.Equal(t, HomeTeamGoal, HomeTeam.Goal())
+	assert.Equal(t, AwayTeamGoal, AwayTeam.Goal())
+	assert.Equal(t, AwayTeamGoal, HomeTeam.AttackGoal())
+	assert.Equal(t, HomeTeamGoal, AwayTeam.AttackGoal())
+	assert.Equal(t, HomeTeamGoal, TeamPlace("").Goal())
+
+	assert.Nil(t, HomeTeam.Validate())
+	assert.Equal(t, ErrInvalidTeamPlace, errors.Cause(TeamPlace("left").Validate()))
+}
+
+func TestPlayerSpecifications_Validate(t *testing.T) {
+	specs := PlayerSpecifications{Number: "7", InitialCoords: physics.Point{PosX: 5000, PosY: 5000}, ProtocolVersion: "1.0"}
+	assert.Nil(t, specs.Validate())
+
+	invalid := specs
+	invalid.Number = "12"
+	assert.Equal(t, ErrPlayerNumberOutOfRange, errors.Cause(invalid.Validate()))
+
+	// a version without profile is rejected, while no version means the default rules
+	newer := specs
+	newer.ProtocolVersion = "9.0"
+	assert.Equal(t, ErrUnknownProtocolVersion, errors.Cause(newer.Validate()))
+	assert.Nil(t, PlayerSpecifications{Number: "7"}.Validate())
+	assert.Nil(t, specs.ValidateProfile())
+	assert.Equal(t, ErrUnknownProtocolVersion, errors.Cause(newer.ValidateProfile()))
+	assert.Equal(t, ErrUnknownProtocolVersion, errors.Cause(PlayerSpecifications{Number: "7"}.ValidateProfile()))
+	assert.Equal(t, ErrPlayerNumberOutOfRange, errors.Cause(PlayerSpecifications{Number: "12", ProtocolVersion: "1.0"}.ValidateProfile()))
+
+	invalid = specs
+	invalid.InitialCoords = physics.Point{PosX: -10, PosY: 5000}
+	assert.NotNil(t, invalid.Validate())
+}
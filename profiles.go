@@ -0,0 +1,142 @@
+package arena
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/lugobots/arena/units"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownProtocolVersion is returned (wrapped, see errors.Cause) when there is no rule profile registered for a
+// protocol version
+var ErrUnknownProtocolVersion = errors.New("there is no rule profile for the protocol version")
+
+// WelcomeProtocolVersionKey is the key of the WELCOME message data that holds the server protocol version
+const WelcomeProtocolVersionKey = "protocol_version"
+
+// WelcomeRulesKey is the key of the WELCOME message data that may hold the rules used by the server. The values
+// sent by the server replace the ones from the profile.
+const WelcomeRulesKey = "rules"
+
+// Profile is a named set of rules used by the game servers of a protocol version
+type Profile struct {
+	// Name identifies the profile (e.g. lugo-1)
+	Name string
+	// ProtocolVersion is the version of the servers using this profile. A version matches all versions that start
+	// with the same numbers, e.g. "1" matches "1.0" and "1.2.3", while "1.2" only matches "1.2" and "1.2.*"
+	ProtocolVersion string
+	// Rules are the game values used by the servers
+	Rules units.Rules
+}
+
+// ProfileRegistry keeps the rule profiles indexed by protocol version
+type ProfileRegistry struct {
+	mutex    sync.RWMutex
+	profiles map[string]Profile
+}
+
+// NewProfileRegistry creates an empty registry
+func NewProfileRegistry() *ProfileRegistry {
+	return &ProfileRegistry{profiles: map[string]Profile{}}
+}
+
+// Profiles is the registry used by the package functions. It has the profiles of the known Lugo versions.
+var Profiles = NewProfileRegistry()
+
+func init() {
+	if err := Profiles.Register(Profile{Name: "lugo-1", ProtocolVersion: "1", Rules: units.DefaultRules()}); err != nil {
+		panic(err)
+	}
+}
+
+// Register adds a profile to the registry. A protocol version may have only one profile.
+func (r *ProfileRegistry) Register(profile Profile) error {
+	version := normalizeVersion(profile.ProtocolVersion)
+	if version == "" {
+		return fmt.Errorf("the profile %s has no protocol version", profile.Name)
+	}
+	if err := profile.Rules.Validate(); err != nil {
+		return fmt.Errorf("invalid profile %s: %s", profile.Name, err)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if registered, ok := r.profiles[version]; ok {
+		return fmt.Errorf("the protocol version %s already has the profile %s", version, registered.Name)
+	}
+	profile.ProtocolVersion = version
+	r.profiles[version] = profile
+	return nil
+}
+
+// Lookup finds the profile of the protocol version. The most specific registered version is used, e.g. the profile
+// for "1.2" is preferred to the one for "1" when looking up "1.2.3".
+func (r *ProfileRegistry) Lookup(protocolVersion string) (Profile, error) {
+	version := normalizeVersion(protocolVersion)
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	for version != "" {
+		if profile, ok := r.profiles[version]; ok {
+			return profile, nil
+		}
+		if i := strings.LastIndex(version, "."); i >= 0 {
+			version = version[:i]
+		} else {
+			version = ""
+		}
+	}
+	return Profile{}, errors.Wrapf(ErrUnknownProtocolVersion, "version %q", protocolVersion)
+}
+
+// Versions lists the registered protocol versions in alphabetical order
+func (r *ProfileRegistry) Versions() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	versions := make([]string, 0, len(r.profiles))
+	for version := range r.profiles {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// ForSpecifications finds the profile of the protocol version the player is compatible with
+func (r *ProfileRegistry) ForSpecifications(specs PlayerSpecifications) (Profile, error) {
+	return r.Lookup(specs.ProtocolVersion)
+}
+
+// ForWelcome finds the profile of the protocol version announced in the WELCOME message. When the message has no
+// version, `fallback` (e.g. the player specifications version) is used. The rules sent in the message, if any,
+// replace the profile values, and are read as strictly as units.ParseRulesJSON does.
+func (r *ProfileRegistry) ForWelcome(msg GameMessage, fallback string) (Profile, error) {
+	version := fallback
+	if announced, ok := msg.Data[WelcomeProtocolVersionKey].(string); ok && announced != "" {
+		version = announced
+	}
+	profile, err := r.Lookup(version)
+	if err != nil {
+		return Profile{}, err
+	}
+	if rules, ok := msg.Data[WelcomeRulesKey]; ok {
+		raw, err := json.Marshal(rules)
+		if err != nil {
+			return Profile{}, fmt.Errorf("invalid rules in the welcome message: %s", err)
+		}
+		if profile.Rules, err = units.ParseRulesJSONOver(profile.Rules, raw); err != nil {
+			return Profile{}, fmt.Errorf("invalid welcome message: %s", err)
+		}
+	}
+	return profile, nil
+}
+
+// LookupProfile finds the profile of the protocol version in the default registry
+func LookupProfile(protocolVersion string) (Profile, error) {
+	return Profiles.Lookup(protocolVersion)
+}
+
+// normalizeVersion removes the spaces and the "v" prefix (e.g. "v1.0" is "1.0")
+func normalizeVersion(version string) string {
+	return strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
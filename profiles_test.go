@@ -0,0 +1,92 @@
+package arena
+
+import (
+	"github.com/lugobots/arena/units"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestRegistry(t *testing.T) *ProfileRegistry {
+	registry := NewProfileRegistry()
+	assert.Nil(t, registry.Register(Profile{Name: "classic", ProtocolVersion: "1", Rules: units.DefaultRules()}))
+	wide := units.DefaultRules()
+	wide.FieldWidth = 30000
+	assert.Nil(t, registry.Register(Profile{Name: "wide", ProtocolVersion: "v1.2", Rules: wide}))
+	return registry
+}
+
+func TestProfileRegistry_Lookup(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	profile, err := registry.Lookup("1.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "classic", profile.Name)
+
+	profile, err = registry.Lookup("1.2.3")
+	assert.Nil(t, err)
+	assert.Equal(t, "wide", profile.Name)
+	assert.Equal(t, 30000, profile.Rules.FieldWidth)
+
+	profile, err = registry.Lookup(" 1.2 ")
+	assert.Nil(t, err)
+	assert.Equal(t, "wide", profile.Name)
+
+	_, err = registry.Lookup("2.0")
+	assert.Equal(t, ErrUnknownProtocolVersion, errors.Cause(err))
+	assert.Contains(t, err.Error(), `"2.0"`)
+	_, err = registry.Lookup("")
+	assert.Equal(t, ErrUnknownProtocolVersion, errors.Cause(err))
+
+	assert.Equal(t, []string{"1", "1.2"}, registry.Versions())
+}
+
+func TestProfileRegistry_Register(t *testing.T) {
+	registry := newTestRegistry(t)
+	assert.NotNil(t, registry.Register(Profile{Name: "again", ProtocolVersion: "1", Rules: units.DefaultRules()}))
+	assert.NotNil(t, registry.Register(Profile{Name: "no version", Rules: units.DefaultRules()}))
+	assert.NotNil(t, registry.Register(Profile{Name: "invalid", ProtocolVersion: "3", Rules: units.Rules{}}))
+}
+
+func TestProfileRegistry_ForSpecifications(t *testing.T) {
+	profile, err := newTestRegistry(t).ForSpecifications(PlayerSpecifications{ProtocolVersion: "1.2"})
+	assert.Nil(t, err)
+	assert.Equal(t, "wide", profile.Name)
+}
+
+func TestProfileRegistry_ForWelcome(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	profile, err := registry.ForWelcome(GameMessage{Type: "welcome"}, "1.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "classic", profile.Name)
+
+	profile, err = registry.ForWelcome(GameMessage{Type: "welcome", Data: map[string]interface{}{
+		WelcomeProtocolVersionKey: "1.2.1",
+		WelcomeRulesKey:           map[string]interface{}{"ball_max_speed": 500.0},
+	}}, "1.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "wide", profile.Name)
+	assert.Equal(t, 30000, profile.Rules.FieldWidth)
+	assert.Equal(t, 500.0, profile.Rules.BallMaxSpeed)
+
+	_, err = registry.ForWelcome(GameMessage{Type: "welcome", Data: map[string]interface{}{
+		WelcomeRulesKey: map[string]interface{}{"field_width": -1},
+	}}, "1.0")
+	assert.NotNil(t, err)
+
+	_, err = registry.ForWelcome(GameMessage{Type: "welcome", Data: map[string]interface{}{
+		WelcomeRulesKey: map[string]interface{}{"goal_size": 4000},
+	}}, "1.0")
+	assert.EqualError(t, err, `invalid welcome message: invalid rules: json: unknown field "goal_size"`)
+
+	_, err = registry.ForWelcome(GameMessage{Type: "welcome"}, "9")
+	assert.Equal(t, ErrUnknownProtocolVersion, errors.Cause(err))
+}
+
+func TestLookupProfile(t *testing.T) {
+	profile, err := LookupProfile("1.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "lugo-1", profile.Name)
+	assert.Equal(t, units.DefaultRules(), profile.Rules)
+}
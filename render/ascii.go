@@ -0,0 +1,248 @@
+// Package render draws game snapshots to help watching and debugging the matches.
+package render
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"math"
+	"strings"
+)
+
+// DefaultColumns is the width of the field drawn by the ASCII renderer when no other width is specified
+const DefaultColumns = 100
+
+// ASCII draws snapshots as text to be shown in a terminal. The field is scaled to fit the number of columns keeping
+// its proportions, considering that a terminal character is about twice as tall as it is wide.
+type ASCII struct {
+	// Columns is the number of characters used to draw the field width (borders not included)
+	Columns int
+	// Rows is the number of lines used to draw the field height (borders not included)
+	Rows int
+	// Arrows enables the velocity arrows drawn beside the moving elements
+	Arrows bool
+	// Colors enables the terminal colors. The players numbers are not distinguishable by team without colors.
+	Colors bool
+
+	field  arena.Field
+	styles map[style]*color.Color
+}
+
+// NewASCII creates an ASCII renderer with the number of columns, the number of rows is derived from the field size
+func NewASCII(columns int) *ASCII {
+	return NewASCIIWithRules(columns, units.DefaultRules())
+}
+
+// NewASCIIWithRules creates an ASCII renderer that draws the field defined by the rules (e.g. the rules in a replay
+// header)
+func NewASCIIWithRules(columns int, rules units.Rules) *ASCII {
+	if columns <= 0 {
+		columns = DefaultColumns
+	}
+	field := arena.NewFieldFromRules(rules)
+	rows := int(math.Round(float64(columns*field.Height) / float64(field.Width) / 2))
+	if rows < 1 {
+		rows = 1
+	}
+	return &ASCII{
+		Columns: columns,
+		Rows:    rows,
+		Colors:  true,
+		field:   field,
+		styles: map[style]*color.Color{
+			homeStyle:   color.New(color.FgHiBlue, color.Bold),
+			awayStyle:   color.New(color.FgHiRed, color.Bold),
+			holderStyle: color.New(color.FgHiYellow, color.Bold, color.Underline),
+			ballStyle:   color.New(color.FgHiYellow, color.Bold),
+			lineStyle:   color.New(color.FgGreen),
+			arrowStyle:  color.New(color.FgWhite),
+		},
+	}
+}
+
+type style int
+
+const (
+	plainStyle style = iota
+	homeStyle
+	awayStyle
+	holderStyle
+	ballStyle
+	lineStyle
+	arrowStyle
+)
+
+type cell struct {
+	char  rune
+	style style
+}
+
+// Render draws the snapshot. The home team goal is on the left side, and the Y axis grows upwards.
+func (r *ASCII) Render(snapshot arena.Snapshot) string {
+	canvas := r.newCanvas()
+	r.drawField(canvas)
+
+	holder := snapshot.Ball.Holder
+	if r.Arrows {
+		for _, player := range snapshot.Players() {
+			r.drawArrow(canvas, player.Element)
+		}
+		if holder == nil {
+			r.drawArrow(canvas, snapshot.Ball.Element)
+		}
+	}
+	for _, player := range snapshot.Players() {
+		s := homeStyle
+		if player.TeamPlace == arena.AwayTeam {
+			s = awayStyle
+		}
+		if holder != nil && holder.TeamPlace == player.TeamPlace && holder.Number == player.Number {
+			s = holderStyle
+		}
+		col, row := r.cellOf(player.Coords)
+		for i, char := range string(player.Number) {
+			canvas.set(col+i, row, char, s)
+		}
+	}
+	if holder == nil {
+		col, row := r.cellOf(snapshot.Ball.Coords)
+		canvas.set(col, row, 'o', ballStyle)
+	}
+	return r.print(canvas)
+}
+
+// Header returns a line with the turn, the game state and the score of the snapshot
+func (r *ASCII) Header(snapshot arena.Snapshot) string {
+	home := r.sprint(homeStyle, fmt.Sprintf("%s %d", snapshot.HomeTeam.Name, snapshot.HomeTeam.Score))
+	away := r.sprint(awayStyle, fmt.Sprintf("%d %s", snapshot.AwayTeam.Score, snapshot.AwayTeam.Name))
+	return fmt.Sprintf("turn %d [%s] %s x %s", snapshot.Turn, snapshot.State, home, away)
+}
+
+// canvas has the borders around the field cells: the field cell (0, 0) is the canvas cell (1, 1)
+type canvas struct {
+	cells [][]cell
+}
+
+func (r *ASCII) newCanvas() *canvas {
+	c := &canvas{cells: make([][]cell, r.Rows+2)}
+	for i := range c.cells {
+		c.cells[i] = make([]cell, r.Columns+2)
+		for j := range c.cells[i] {
+			c.cells[i][j] = cell{char: ' '}
+		}
+	}
+	return c
+}
+
+// set changes a field cell, the cells out of the field are ignored
+func (c *canvas) set(col, row int, char rune, s style) {
+	if row < 0 || row >= len(c.cells)-2 || col < 0 || col >= len(c.cells[0])-2 {
+		return
+	}
+	c.cells[row+1][col+1] = cell{char: char, style: s}
+}
+
+func (c *canvas) isEmpty(col, row int) bool {
+	if row < 0 || row >= len(c.cells)-2 || col < 0 || col >= len(c.cells[0])-2 {
+		return false
+	}
+	char := c.cells[row+1][col+1].char
+	return char == ' ' || char == ':'
+}
+
+func (r *ASCII) drawField(c *canvas) {
+	last := len(c.cells) - 1
+	width := len(c.cells[0]) - 1
+	for col := 0; col <= width; col++ {
+		c.cells[0][col] = cell{char: '-', style: lineStyle}
+		c.cells[last][col] = cell{char: '-', style: lineStyle}
+	}
+	for row := 0; row <= last; row++ {
+		c.cells[row][0] = cell{char: '|', style: lineStyle}
+		c.cells[row][width] = cell{char: '|', style: lineStyle}
+	}
+	for _, corner := range [][2]int{{0, 0}, {0, width}, {last, 0}, {last, width}} {
+		c.cells[corner[0]][corner[1]] = cell{char: '+', style: lineStyle}
+	}
+	centerCol, _ := r.cellOf(r.field.Center)
+	for row := 0; row < r.Rows; row++ {
+		c.set(centerCol, row, ':', lineStyle)
+	}
+
+	// the goals replace the side borders between their poles
+	for _, goal := range []arena.Goal{r.field.HomeGoal, r.field.AwayGoal} {
+		_, topRow := r.cellOf(goal.TopPole)
+		_, bottomRow := r.cellOf(goal.BottomPole)
+		char, col, s := '[', 0, homeStyle
+		if goal.Place == arena.AwayTeam {
+			char, col, s = ']', width, awayStyle
+		}
+		for row := topRow; row <= bottomRow; row++ {
+			c.cells[row+1][col] = cell{char: char, style: s}
+		}
+	}
+}
+
+// drawArrow draws the direction of the element velocity in the cell beside it
+func (r *ASCII) drawArrow(c *canvas, e physics.Element) {
+	if e.Velocity.Direction == nil || e.Velocity.Speed == 0 {
+		return
+	}
+	angle := math.Atan2(e.Velocity.Direction.GetY(), e.Velocity.Direction.GetX())
+	sector := int(math.Round(angle/(math.Pi/4))+8) % 8
+	// the rows grow downwards
+	offsets := [8][2]int{{1, 0}, {1, -1}, {0, -1}, {-1, -1}, {-1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+	chars := [8]rune{'>', '/', '^', '\\', '<', '/', 'v', '\\'}
+	col, row := r.cellOf(e.Coords)
+	col, row = col+offsets[sector][0], row+offsets[sector][1]
+	if c.isEmpty(col, row) {
+		c.set(col, row, chars[sector], arrowStyle)
+	}
+}
+
+// cellOf finds the field cell that contains the point
+func (r *ASCII) cellOf(p physics.Point) (int, int) {
+	col := int(float64(p.PosX) * float64(r.Columns) / float64(r.field.Width))
+	row := r.Rows - 1 - int(float64(p.PosY)*float64(r.Rows)/float64(r.field.Height))
+	return clamp(col, 0, r.Columns-1), clamp(row, 0, r.Rows-1)
+}
+
+func (r *ASCII) print(c *canvas) string {
+	var builder strings.Builder
+	for _, line := range c.cells {
+		for i := 0; i < len(line); {
+			// consecutive cells with the same style are printed together
+			j := i
+			var run []rune
+			for ; j < len(line) && line[j].style == line[i].style; j++ {
+				run = append(run, line[j].char)
+			}
+			builder.WriteString(r.sprint(line[i].style, string(run)))
+			i = j
+		}
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+func (r *ASCII) sprint(s style, text string) string {
+	c, ok := r.styles[s]
+	if !r.Colors || !ok {
+		return text
+	}
+	// the renderer colors do not depend on the output being a terminal
+	c.EnableColor()
+	return c.Sprint(text)
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
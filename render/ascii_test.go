@@ -0,0 +1,101 @@
+package render
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestSnapshot() arena.Snapshot {
+	return arena.Snapshot{
+		Turn:  42,
+		State: arena.Listening,
+		Ball: arena.Ball{Element: physics.Element{
+			Size:     units.BallSize,
+			Coords:   physics.Point{PosX: 12000, PosY: 7000},
+			Velocity: physics.NewZeroedVelocity(physics.East),
+		}},
+		HomeTeam: arena.Team{Place: arena.HomeTeam, Name: "Home", Score: 2, Players: []arena.Player{
+			{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 500, PosY: 5000}, Velocity: physics.NewZeroedVelocity(physics.East)}, Number: "1", TeamPlace: arena.HomeTeam},
+			{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 6000, PosY: 9000}, Velocity: physics.NewZeroedVelocity(physics.East)}, Number: "7", TeamPlace: arena.HomeTeam},
+		}},
+		AwayTeam: arena.Team{Place: arena.AwayTeam, Name: "Away", Score: 1, Players: []arena.Player{
+			{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 15000, PosY: 1000}, Velocity: physics.NewZeroedVelocity(physics.East)}, Number: "10", TeamPlace: arena.AwayTeam},
+		}},
+	}
+}
+
+func TestASCII_Render(t *testing.T) {
+	renderer := NewASCII(20)
+	renderer.Colors = false
+	assert.Equal(t, 5, renderer.Rows)
+
+	expected := "" +
+		"+--------------------+\n" +
+		"|      7   :         |\n" +
+		"[          : o       ]\n" +
+		"[1         :         ]\n" +
+		"[          :         ]\n" +
+		"|          :    10   |\n" +
+		"+--------------------+\n"
+	assert.Equal(t, expected, renderer.Render(newTestSnapshot()))
+}
+
+func TestASCII_RenderHolderAndArrows(t *testing.T) {
+	renderer := NewASCII(20)
+	renderer.Colors = false
+	renderer.Arrows = true
+	snapshot := newTestSnapshot()
+	holder := snapshot.Player(arena.AwayTeam, "10")
+	holder.Velocity = physics.NewZeroedVelocity(physics.West)
+	holder.Velocity.Speed = units.PlayerMaxSpeed
+	held := holder.Copy()
+	snapshot.Ball.Holder = &held
+
+	expected := "" +
+		"+--------------------+\n" +
+		"|      7   :         |\n" +
+		"[          :         ]\n" +
+		"[1         :         ]\n" +
+		"[          :         ]\n" +
+		"|          :   <10   |\n" +
+		"+--------------------+\n"
+	assert.Equal(t, expected, renderer.Render(snapshot))
+}
+
+func TestASCII_Colors(t *testing.T) {
+	renderer := NewASCII(20)
+	drawing := renderer.Render(newTestSnapshot())
+	assert.Contains(t, drawing, "\x1b[")
+	assert.Contains(t, renderer.Header(newTestSnapshot()), "Home 2")
+
+	renderer.Colors = false
+	assert.Equal(t, "turn 42 [listening] Home 2 x 1 Away", renderer.Header(newTestSnapshot()))
+}
+
+func TestNewASCIIWithRules(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.FieldWidth = 10000
+	rules.FieldHeight = 5000
+	renderer := NewASCIIWithRules(20, rules)
+	renderer.Colors = false
+	assert.Equal(t, 5, renderer.Rows)
+
+	snapshot := arena.Snapshot{
+		Ball: arena.Ball{Element: physics.Element{Size: units.BallSize, Coords: physics.Point{PosX: 5000, PosY: 2500}}},
+		AwayTeam: arena.Team{Place: arena.AwayTeam, Players: []arena.Player{
+			{Element: physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: 9000, PosY: 4500}}, Number: "9", TeamPlace: arena.AwayTeam},
+		}},
+	}
+	expected := "" +
+		"+--------------------+\n" +
+		"[          :       9 ]\n" +
+		"[          :         ]\n" +
+		"[          o         ]\n" +
+		"[          :         ]\n" +
+		"|          :         |\n" +
+		"+--------------------+\n"
+	assert.Equal(t, expected, renderer.Render(snapshot))
+}
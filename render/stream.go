@@ -0,0 +1,97 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/replay"
+	"github.com/lugobots/arena/talk"
+	"io"
+	"time"
+)
+
+// clearScreen moves the terminal cursor to the top and clears the screen
+const clearScreen = "\033[H\033[2J"
+
+// WriteFrame clears the terminal and draws the snapshot with its header
+func (r *ASCII) WriteFrame(out io.Writer, snapshot arena.Snapshot) error {
+	return r.writeFrame(out, snapshot, r.Render(snapshot))
+}
+
+// writeFrame clears the terminal and writes the snapshot header followed by its drawing
+func (r *ASCII) writeFrame(out io.Writer, snapshot arena.Snapshot, drawing string) error {
+	_, err := fmt.Fprint(out, clearScreen+r.Header(snapshot)+"\n"+drawing)
+	return err
+}
+
+// Live draws every snapshot announced by the game server through the talker until the context is done, the
+// connection is interrupted, or the talker stops sending messages. Messages that cannot be decoded are ignored.
+func Live(ctx context.Context, talker talk.Talker, renderer *ASCII, out io.Writer) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-talker.ListenInterruption():
+			return nil
+		case raw, ok := <-talker.Listen():
+			if !ok {
+				return nil
+			}
+			var msg arena.GameMessage
+			if err := json.Unmarshal(raw, &msg); err != nil || msg.Type != orders.ANNOUNCEMENT {
+				continue
+			}
+			if err := renderer.WriteFrame(out, msg.Snapshot); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Frames calls `frame` with the drawing of each snapshot announced in the replay starting at the turn `from`.
+// Returning an error from `frame` stops the iteration, and that error is returned unless it is io.EOF.
+func Frames(reader *replay.Reader, renderer *ASCII, from int, frame func(snapshot arena.Snapshot, drawing string) error) error {
+	if err := reader.SeekTurn(from); err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for {
+		entry, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if entry.Kind != replay.MessageEntry {
+			continue
+		}
+		msg, err := entry.GameMessage()
+		if err != nil {
+			return err
+		}
+		if msg.Type != orders.ANNOUNCEMENT {
+			continue
+		}
+		if err := frame(msg.Snapshot, renderer.Render(msg.Snapshot)); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Play draws the replay in the terminal starting at the turn `from`, waiting the interval between the frames
+func Play(reader *replay.Reader, renderer *ASCII, from int, interval time.Duration, out io.Writer) error {
+	return Frames(reader, renderer, from, func(snapshot arena.Snapshot, drawing string) error {
+		if err := renderer.writeFrame(out, snapshot, drawing); err != nil {
+			return err
+		}
+		time.Sleep(interval)
+		return nil
+	})
+}
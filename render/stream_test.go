@@ -0,0 +1,124 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gorilla/websocket"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/replay"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type fakeTalker struct {
+	listen chan []byte
+}
+
+func (f *fakeTalker) Connect(mainCtx context.Context, url url.URL, playerSpec arena.PlayerSpecifications) (context.Context, error) {
+	return mainCtx, nil
+}
+
+func (f *fakeTalker) Send(data []byte) error {
+	return nil
+}
+
+func (f *fakeTalker) Listen() <-chan []byte {
+	return f.listen
+}
+
+func (f *fakeTalker) ListenInterruption() <-chan *websocket.CloseError {
+	return nil
+}
+
+func (f *fakeTalker) Close() {}
+
+func (f *fakeTalker) Profile() arena.Profile {
+	return arena.Profile{}
+}
+
+func (f *fakeTalker) Field() arena.Field {
+	return arena.NewField()
+}
+
+func TestLive(t *testing.T) {
+	talker := &fakeTalker{listen: make(chan []byte, 3)}
+	snapshot := newTestSnapshot()
+	announcement, _ := json.Marshal(arena.GameMessage{Type: orders.ANNOUNCEMENT, Snapshot: snapshot})
+	welcome, _ := json.Marshal(arena.GameMessage{Type: orders.WELCOME, Snapshot: snapshot})
+	talker.listen <- welcome
+	talker.listen <- []byte("not json")
+	talker.listen <- announcement
+	close(talker.listen)
+
+	renderer := NewASCII(20)
+	renderer.Colors = false
+	out := &bytes.Buffer{}
+	assert.Nil(t, Live(context.Background(), talker, renderer, out))
+	assert.Equal(t, clearScreen+"turn 42 [listening] Home 2 x 1 Away\n"+renderer.Render(snapshot), out.String())
+}
+
+func TestFrames(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	writer, err := replay.NewWriter(buffer, replay.Header{}, false)
+	assert.Nil(t, err)
+	snapshot := newTestSnapshot()
+	for turn := 1; turn <= 5; turn++ {
+		snapshot.Turn = turn
+		assert.Nil(t, writer.WriteGameMessage(arena.GameMessage{Type: orders.ANNOUNCEMENT, Snapshot: snapshot}))
+		assert.Nil(t, writer.WriteOrders(turn, nil))
+	}
+	assert.Nil(t, writer.Close())
+
+	reader, err := replay.NewReader(bytes.NewReader(buffer.Bytes()))
+	assert.Nil(t, err)
+	renderer := NewASCII(20)
+	var turns []int
+	err = Frames(reader, renderer, 3, func(snapshot arena.Snapshot, drawing string) error {
+		turns = append(turns, snapshot.Turn)
+		assert.Equal(t, renderer.Rows+2, strings.Count(drawing, "\n"))
+		if snapshot.Turn == 4 {
+			return io.EOF
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{3, 4}, turns)
+
+	turns = nil
+	assert.Nil(t, Frames(reader, renderer, 10, func(snapshot arena.Snapshot, drawing string) error {
+		turns = append(turns, snapshot.Turn)
+		return nil
+	}))
+	assert.Empty(t, turns)
+}
+
+func TestPlay(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	writer, err := replay.NewWriter(buffer, replay.Header{}, false)
+	assert.Nil(t, err)
+	snapshot := newTestSnapshot()
+	for turn := 1; turn <= 2; turn++ {
+		snapshot.Turn = turn
+		assert.Nil(t, writer.WriteGameMessage(arena.GameMessage{Type: orders.ANNOUNCEMENT, Snapshot: snapshot}))
+		assert.Nil(t, writer.WriteOrders(turn, nil))
+	}
+	assert.Nil(t, writer.Close())
+
+	reader, err := replay.NewReader(bytes.NewReader(buffer.Bytes()))
+	assert.Nil(t, err)
+	renderer := NewASCII(20)
+	renderer.Colors = false
+	out := &bytes.Buffer{}
+	assert.Nil(t, Play(reader, renderer, 2, 0, out))
+
+	expected := &bytes.Buffer{}
+	assert.Nil(t, renderer.WriteFrame(expected, snapshot))
+	assert.Equal(t, expected.String(), out.String())
+	// only the escape sequences that clear the screen
+	assert.Equal(t, 2, strings.Count(out.String(), "\033["))
+}
@@ -0,0 +1,356 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/analysis"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/replay"
+	"github.com/lugobots/arena/units"
+	"html"
+	"io"
+	"math"
+)
+
+// DefaultSVGWidth is the width in pixels of the field drawn by the SVG renderer when no other width is specified
+const DefaultSVGWidth = 1000
+
+const (
+	homeColor  = "#1f5fd1"
+	awayColor  = "#d1321f"
+	ballColor  = "#ffffff"
+	lineColor  = "#ffffff"
+	grassColor = "#3a8a3a"
+)
+
+// SVG draws snapshots as SVG images
+type SVG struct {
+	// Width is the image width in pixels, the height is derived from the field size
+	Width int
+	// Vectors enables the velocity vectors of the moving elements
+	Vectors bool
+	// VectorTurns is the number of turns represented by the velocity vectors, the vector ends where the element
+	// would be after these turns keeping its velocity
+	VectorTurns float64
+
+	field arena.Field
+}
+
+// NewSVG creates an SVG renderer for images with the width in pixels
+func NewSVG(width int) *SVG {
+	return NewSVGWithRules(width, units.DefaultRules())
+}
+
+// NewSVGWithRules creates an SVG renderer that draws the field defined by the rules (e.g. the rules in a replay
+// header)
+func NewSVGWithRules(width int, rules units.Rules) *SVG {
+	if width <= 0 {
+		width = DefaultSVGWidth
+	}
+	return &SVG{
+		Width:       width,
+		VectorTurns: 10,
+		field:       arena.NewFieldFromRules(rules),
+	}
+}
+
+// Overlay draws extra information over the field, e.g. pass lanes or heatmaps
+type Overlay interface {
+	DrawSVG(d *Drawing)
+}
+
+// Snapshot writes the image of the snapshot with the overlays drawn over the field and under the players
+func (s *SVG) Snapshot(w io.Writer, snapshot arena.Snapshot, overlays ...Overlay) error {
+	return s.Window(w, []arena.Snapshot{snapshot}, overlays...)
+}
+
+// Window writes the image of the last snapshot of a sequence of turns. The ball path and the players trails during
+// the turns are drawn behind the elements.
+func (s *SVG) Window(w io.Writer, snapshots []arena.Snapshot, overlays ...Overlay) error {
+	if len(snapshots) == 0 {
+		return fmt.Errorf("there are no snapshots to draw")
+	}
+	d := s.newDrawing(w)
+	d.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", s.Width, s.height(), s.Width, s.height())
+	d.printf(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="9" refY="5" markerWidth="4" markerHeight="4" orient="auto"><path d="M0,0 L10,5 L0,10 z" fill="context-stroke"/></marker></defs>` + "\n")
+	s.drawField(d)
+	for _, overlay := range overlays {
+		overlay.DrawSVG(d)
+	}
+	last := snapshots[len(snapshots)-1]
+	if len(snapshots) > 1 {
+		s.drawTrails(d, snapshots)
+	}
+	s.drawElements(d, last)
+	d.printf("</svg>\n")
+	return d.out.Flush()
+}
+
+// ReadWindow reads the snapshots announced in the replay between the turns `from` and `to` (both included)
+func ReadWindow(reader *replay.Reader, from, to int) ([]arena.Snapshot, error) {
+	var snapshots []arena.Snapshot
+	if err := reader.SeekTurn(from); err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for {
+		entry, err := reader.Next()
+		if err == io.EOF || (err == nil && entry.Turn > to) {
+			return snapshots, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if entry.Kind != replay.MessageEntry {
+			continue
+		}
+		msg, err := entry.GameMessage()
+		if err != nil {
+			return nil, err
+		}
+		if msg.Type == orders.ANNOUNCEMENT {
+			snapshots = append(snapshots, msg.Snapshot)
+		}
+	}
+}
+
+func (s *SVG) height() int {
+	return int(math.Round(float64(s.Width*s.field.Height) / float64(s.field.Width)))
+}
+
+func (s *SVG) newDrawing(w io.Writer) *Drawing {
+	return &Drawing{
+		out:    bufio.NewWriter(w),
+		scale:  float64(s.Width) / float64(s.field.Width),
+		height: s.field.Height,
+	}
+}
+
+func (s *SVG) drawField(d *Drawing) {
+	f := s.field
+	d.printf(`<rect x="0" y="0" width="%d" height="%d" fill="%s"/>`+"\n", s.Width, s.height(), grassColor)
+	d.printf(`<g fill="none" stroke="%s" stroke-width="2">`+"\n", lineColor)
+	d.Line(physics.Point{PosX: f.Center.PosX, PosY: 0}, physics.Point{PosX: f.Center.PosX, PosY: f.Height}, lineColor, 2)
+	d.Circle(f.Center, float64(f.NeutralCenterRadius), "none", lineColor)
+	for _, place := range []arena.TeamPlace{arena.HomeTeam, arena.AwayTeam} {
+		s.drawGoalZone(d, f.GoalZone(place))
+	}
+	d.printf("</g>\n")
+	for _, goal := range []arena.Goal{f.HomeGoal, f.AwayGoal} {
+		c := homeColor
+		if goal.Place == arena.AwayTeam {
+			c = awayColor
+		}
+		d.Line(goal.BottomPole, goal.TopPole, c, 6)
+	}
+}
+
+// drawGoalZone draws the goal zone limits: a line parallel to the goal mouth and a quarter of circle around each pole
+func (s *SVG) drawGoalZone(d *Drawing, zone arena.GoalZone) {
+	direction, sweep := 1, 0
+	if zone.Goal.Place == arena.AwayTeam {
+		direction, sweep = -1, 1
+	}
+	bottom, top := zone.Goal.BottomPole, zone.Goal.TopPole
+	radius := d.length(float64(zone.Range))
+	start := physics.Point{PosX: bottom.PosX, PosY: bottom.PosY - zone.Range}
+	bottomFront := physics.Point{PosX: bottom.PosX + direction*zone.Range, PosY: bottom.PosY}
+	topFront := physics.Point{PosX: top.PosX + direction*zone.Range, PosY: top.PosY}
+	end := physics.Point{PosX: top.PosX, PosY: top.PosY + zone.Range}
+	d.printf(`<path d="M%s A%s,%s 0 0 %d %s L%s A%s,%s 0 0 %d %s"/>`+"\n",
+		d.point(start), radius, radius, sweep, d.point(bottomFront), d.point(topFront), radius, radius, sweep, d.point(end))
+}
+
+func (s *SVG) drawTrails(d *Drawing, snapshots []arena.Snapshot) {
+	last := snapshots[len(snapshots)-1]
+	for _, player := range last.Players() {
+		var trail []physics.Point
+		moved := false
+		for i := range snapshots {
+			if p := snapshots[i].Player(player.TeamPlace, player.Number); p != nil {
+				moved = moved || (len(trail) > 0 && trail[0] != p.Coords)
+				trail = append(trail, p.Coords)
+			}
+		}
+		if moved {
+			d.Polyline(trail, teamColor(player.TeamPlace), 1, 0.5)
+		}
+	}
+	var path []physics.Point
+	for _, snapshot := range snapshots {
+		path = append(path, snapshot.Ball.Coords)
+	}
+	d.Polyline(path, ballColor, 2, 0.8)
+}
+
+func (s *SVG) drawElements(d *Drawing, snapshot arena.Snapshot) {
+	holder := snapshot.Ball.Holder
+	for _, player := range snapshot.Players() {
+		stroke := "#000000"
+		if holder != nil && holder.TeamPlace == player.TeamPlace && holder.Number == player.Number {
+			stroke = "#ffd700"
+		}
+		d.Circle(player.Coords, float64(player.Size)/2, teamColor(player.TeamPlace), stroke)
+		d.Text(player.Coords, string(player.Number), "#ffffff")
+		if s.Vectors {
+			s.drawVector(d, player.Element, "#000000")
+		}
+	}
+	d.Circle(snapshot.Ball.Coords, float64(snapshot.Ball.Size)/2, ballColor, "#000000")
+	if s.Vectors && holder == nil {
+		s.drawVector(d, snapshot.Ball.Element, ballColor)
+	}
+}
+
+func (s *SVG) drawVector(d *Drawing, e physics.Element, stroke string) {
+	if e.Velocity.Direction == nil || e.Velocity.Speed == 0 {
+		return
+	}
+	target := physics.PredictPosition(e, s.VectorTurns)
+	d.printf(`<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="2" marker-end="url(#arrow)"/>`+"\n",
+		d.x(e.Coords.PosX), d.y(e.Coords.PosY), d.x(target.PosX), d.y(target.PosY), stroke)
+}
+
+func teamColor(place arena.TeamPlace) string {
+	if place == arena.AwayTeam {
+		return awayColor
+	}
+	return homeColor
+}
+
+// Drawing writes the SVG elements converting the field coordinates to the image coordinates, whose Y axis grows
+// downwards. The distances are in field units.
+type Drawing struct {
+	out    *bufio.Writer
+	scale  float64
+	height int
+}
+
+// Line draws a segment between two points
+func (d *Drawing) Line(a, b physics.Point, stroke string, width float64) {
+	d.printf(`<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s"/>`+"\n",
+		d.x(a.PosX), d.y(a.PosY), d.x(b.PosX), d.y(b.PosY), stroke, format(width))
+}
+
+// Circle draws a circle, `fill` and `stroke` may be "none"
+func (d *Drawing) Circle(center physics.Point, radius float64, fill, stroke string) {
+	d.printf(`<circle cx="%s" cy="%s" r="%s" fill="%s" stroke="%s"/>`+"\n",
+		d.x(center.PosX), d.y(center.PosY), d.length(radius), fill, stroke)
+}
+
+// Rect draws a filled rectangle with the opacity (0 to 1)
+func (d *Drawing) Rect(r arena.Rect, fill string, opacity float64) {
+	d.printf(`<rect x="%s" y="%s" width="%s" height="%s" fill="%s" fill-opacity="%s"/>`+"\n",
+		d.x(r.Min.PosX), d.y(r.Max.PosY), d.length(float64(r.Max.PosX-r.Min.PosX)), d.length(float64(r.Max.PosY-r.Min.PosY)), fill, format(opacity))
+}
+
+// Polyline draws the path through the points
+func (d *Drawing) Polyline(points []physics.Point, stroke string, width, opacity float64) {
+	if len(points) < 2 {
+		return
+	}
+	d.printf(`<polyline points="`)
+	for i, p := range points {
+		if i > 0 {
+			d.printf(" ")
+		}
+		d.printf("%s", d.point(p))
+	}
+	d.printf(`" fill="none" stroke="%s" stroke-width="%s" stroke-opacity="%s"/>`+"\n", stroke, format(width), format(opacity))
+}
+
+// Text writes a short text centered on the point
+func (d *Drawing) Text(p physics.Point, text, fill string) {
+	d.printf(`<text x="%s" y="%s" fill="%s" font-family="sans-serif" font-size="%s" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+		d.x(p.PosX), d.y(p.PosY), fill, d.length(250), html.EscapeString(text))
+}
+
+func (d *Drawing) printf(f string, args ...interface{}) {
+	fmt.Fprintf(d.out, f, args...)
+}
+
+func (d *Drawing) x(x int) string {
+	return format(float64(x) * d.scale)
+}
+
+func (d *Drawing) y(y int) string {
+	return format(float64(d.height-y) * d.scale)
+}
+
+func (d *Drawing) length(l float64) string {
+	return format(l * d.scale)
+}
+
+func (d *Drawing) point(p physics.Point) string {
+	return d.x(p.PosX) + "," + d.y(p.PosY)
+}
+
+// format writes the numbers with one decimal place at most, keeping the output stable
+func format(value float64) string {
+	rounded := math.Round(value*10) / 10
+	if rounded == math.Trunc(rounded) {
+		return fmt.Sprintf("%.0f", rounded)
+	}
+	return fmt.Sprintf("%.1f", rounded)
+}
+
+// PassLane is an overlay that draws the path of a pass, dashed when it is not safe
+type PassLane struct {
+	From physics.Point
+	To   physics.Point
+	Safe bool
+}
+
+// DrawSVG draws the pass lane
+func (p PassLane) DrawSVG(d *Drawing) {
+	c, dash := "#ffd700", ""
+	if !p.Safe {
+		c, dash = "#ff4500", ` stroke-dasharray="8,6"`
+	}
+	d.printf(`<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="3"%s marker-end="url(#arrow)"/>`+"\n",
+		d.x(p.From.PosX), d.y(p.From.PosY), d.x(p.To.PosX), d.y(p.To.PosY), c, dash)
+}
+
+// Heatmap is an overlay that paints square cells covering the field. Values[col][row] is the intensity of the cell
+// whose lowest corner is (col*CellSize, row*CellSize), from 0 (transparent) to 1.
+type Heatmap struct {
+	CellSize int
+	Values   [][]float64
+	Color    string
+}
+
+// DrawSVG draws the heatmap cells
+func (h Heatmap) DrawSVG(d *Drawing) {
+	c := h.Color
+	if c == "" {
+		c = "#ffff00"
+	}
+	for col, values := range h.Values {
+		for row, value := range values {
+			if value <= 0 {
+				continue
+			}
+			min := physics.Point{PosX: col * h.CellSize, PosY: row * h.CellSize}
+			max := physics.Point{PosX: min.PosX + h.CellSize, PosY: min.PosY + h.CellSize}
+			d.Rect(arena.Rect{Min: min, Max: max}, c, math.Min(value, 1)*0.6)
+		}
+	}
+}
+
+// ControlHeatmap creates a heatmap of the cells of the control map owned by the team. The cells where the team
+// arrives well before the opponents are painted stronger.
+func ControlHeatmap(m *analysis.ControlMap, place arena.TeamPlace) Heatmap {
+	heatmap := Heatmap{CellSize: m.CellSize(), Values: make([][]float64, m.Cols()), Color: teamColor(place)}
+	for col := range heatmap.Values {
+		heatmap.Values[col] = make([]float64, m.Rows())
+		for row := range heatmap.Values[col] {
+			if cell := m.Cell(col, row); cell.Owner == place {
+				heatmap.Values[col][row] = 0.3 + 0.7*math.Min(cell.Margin, 10)/10
+			}
+		}
+	}
+	return heatmap
+}
@@ -0,0 +1,107 @@
+package render
+
+import (
+	"bytes"
+	"flag"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/analysis"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/replay"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func assertGolden(t *testing.T, name string, result []byte) {
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := ioutil.WriteFile(golden, result, 0644); err != nil {
+			t.Fatalf("fail on updating the golden file: %s", err)
+		}
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("fail on reading the golden file: %s", err)
+	}
+	assert.Equal(t, string(expected), string(result))
+}
+
+func TestSVG_Snapshot(t *testing.T) {
+	snapshot := newTestSnapshot()
+	snapshot.Ball.Velocity = physics.NewZeroedVelocity(physics.North)
+	snapshot.Ball.Velocity.Speed = units.BallMaxSpeed
+	snapshot.Player(arena.HomeTeam, "7").Velocity.Speed = units.PlayerMaxSpeed
+
+	renderer := NewSVG(500)
+	renderer.Vectors = true
+	out := &bytes.Buffer{}
+	assert.Nil(t, renderer.Snapshot(out, snapshot))
+	assertGolden(t, "snapshot.svg", out.Bytes())
+}
+
+func TestSVG_Overlays(t *testing.T) {
+	snapshot := newTestSnapshot()
+	holder := snapshot.Player(arena.HomeTeam, "7").Copy()
+	snapshot.Ball.Holder = &holder
+	snapshot.Ball.Coords = holder.Coords
+
+	renderer := NewSVG(500)
+	out := &bytes.Buffer{}
+	assert.Nil(t, renderer.Snapshot(out, snapshot,
+		ControlHeatmap(analysis.NewControlMap(snapshot, 20), arena.HomeTeam),
+		PassLane{From: holder.Coords, To: snapshot.Player(arena.HomeTeam, "1").Coords, Safe: true},
+		PassLane{From: holder.Coords, To: physics.Point{PosX: 14000, PosY: 2000}},
+	))
+	assertGolden(t, "overlays.svg", out.Bytes())
+}
+
+func TestSVG_Window(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	writer, err := replay.NewWriter(buffer, replay.Header{}, false)
+	assert.Nil(t, err)
+	snapshot := newTestSnapshot()
+	for turn := 1; turn <= 8; turn++ {
+		snapshot.Turn = turn
+		snapshot.Ball.Coords.PosX -= 300
+		snapshot.Ball.Coords.PosY -= 200
+		snapshot.Player(arena.AwayTeam, "10").Coords.PosY += 100
+		assert.Nil(t, writer.WriteGameMessage(arena.GameMessage{Type: orders.ANNOUNCEMENT, Snapshot: snapshot.Copy()}))
+	}
+	assert.Nil(t, writer.Close())
+
+	reader, err := replay.NewReader(bytes.NewReader(buffer.Bytes()))
+	assert.Nil(t, err)
+	window, err := ReadWindow(reader, 3, 6)
+	assert.Nil(t, err)
+	assert.Len(t, window, 4)
+	assert.Equal(t, 3, window[0].Turn)
+	assert.Equal(t, 6, window[3].Turn)
+
+	out := &bytes.Buffer{}
+	assert.Nil(t, NewSVG(500).Window(out, window))
+	assertGolden(t, "window.svg", out.Bytes())
+
+	assert.NotNil(t, NewSVG(500).Window(out, nil))
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "12", format(12))
+	assert.Equal(t, "12.3", format(12.34))
+	assert.Equal(t, "-0.5", format(-0.46))
+	assert.Equal(t, "1", format(0.96))
+}
+
+func TestNewSVGWithRules(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.FieldWidth = 10000
+	rules.FieldHeight = 5000
+	renderer := NewSVGWithRules(400, rules)
+	out := &bytes.Buffer{}
+	assert.Nil(t, renderer.Snapshot(out, arena.Snapshot{}))
+	assert.Contains(t, out.String(), `width="400" height="200" viewBox="0 0 400 200"`)
+}
@@ -0,0 +1,88 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/units"
+	"time"
+)
+
+// FormatVersion is the version of the replay file format written by this package
+const FormatVersion = 1
+
+// EntryKind identifies the kind of an entry in the replay file
+type EntryKind string
+
+const (
+	// HeaderEntry is the first entry of every replay file, it holds the match metadata
+	HeaderEntry EntryKind = "header"
+	// MessageEntry holds a message sent by the game server (e.g. announcements)
+	MessageEntry EntryKind = "message"
+	// OrdersEntry holds the orders sent by the players in a turn
+	OrdersEntry EntryKind = "orders"
+)
+
+// Rules are the game constants used by the server when the match was recorded
+type Rules = units.Rules
+
+// CurrentRules returns the rules defined by the units package
+func CurrentRules() Rules {
+	return units.DefaultRules()
+}
+
+// Header is the metadata of a recorded match
+type Header struct {
+	// Version is the replay file format version
+	Version int `json:"version"`
+	// ProtocolVersion is the game server communication version used in the match
+	ProtocolVersion string `json:"protocol_version"`
+	// HomeTeam is the home team name
+	HomeTeam string `json:"home_team"`
+	// AwayTeam is the away team name
+	AwayTeam string `json:"away_team"`
+	// Rules are the game constants used in the match
+	Rules Rules `json:"rules"`
+	// Deterministic is set when the match was played with the fixed-point physics (see sim.Config), so it may be
+	// verified with the same bit-identical results
+	Deterministic bool `json:"deterministic,omitempty"`
+	// RecordedAt is when the recording started
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// MatchRules returns the rules used in the match, or the current rules when the header has none (e.g. replays
+// recorded before the rules were added to the header). An error is returned when the rules are invalid.
+func (h Header) MatchRules() (Rules, error) {
+	if h.Rules == (Rules{}) {
+		return CurrentRules(), nil
+	}
+	if err := h.Rules.Validate(); err != nil {
+		return Rules{}, err
+	}
+	return h.Rules, nil
+}
+
+// Entry is a line of the replay file
+type Entry struct {
+	// Kind identifies the entry content
+	Kind EntryKind `json:"kind"`
+	// Turn is the game turn when the entry was recorded
+	Turn int `json:"turn"`
+	// Header is only set in the header entry
+	Header *Header `json:"header,omitempty"`
+	// Message is the raw message sent by the game server, only set in message entries
+	Message json.RawMessage `json:"message,omitempty"`
+	// Batches are the orders sent by the players, only set in orders entries
+	Batches []orders.Batch `json:"batches,omitempty"`
+}
+
+// GameMessage decodes the message of a message entry
+func (e *Entry) GameMessage() (arena.GameMessage, error) {
+	var msg arena.GameMessage
+	if e.Kind != MessageEntry {
+		return msg, fmt.Errorf("entry of kind %s has no message", e.Kind)
+	}
+	err := json.Unmarshal(e.Message, &msg)
+	return msg, err
+}
@@ -0,0 +1,154 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+)
+
+// gzipMagic are the first bytes of any gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// turnOffset is the position (in the uncompressed stream) of the first entry of a turn
+type turnOffset struct {
+	turn   int
+	offset int64
+}
+
+// Reader reads a replay file, compressed or not. Besides reading the entries in sequence, it may seek the entries of
+// a turn. The offsets of the turns already read are indexed, so seeking backwards is cheap.
+type Reader struct {
+	source     io.ReadSeeker
+	compressed bool
+	lines      *bufio.Reader
+	offset     int64
+	header     Header
+	index      []turnOffset
+	indexed    int64
+}
+
+// NewReader creates a reader and reads the replay header
+func NewReader(source io.ReadSeeker) (*Reader, error) {
+	r := &Reader{source: source}
+	if err := r.rewind(); err != nil {
+		return nil, err
+	}
+	entry, err := r.Next()
+	if err != nil {
+		return nil, fmt.Errorf("fail on reading the replay header: %s", err.Error())
+	}
+	if entry.Kind != HeaderEntry || entry.Header == nil {
+		return nil, fmt.Errorf("the replay must start with a header entry")
+	}
+	if entry.Header.Version > FormatVersion {
+		return nil, fmt.Errorf("unsupported replay version %d", entry.Header.Version)
+	}
+	r.header = *entry.Header
+	return r, nil
+}
+
+// Header returns the match metadata
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Next reads the next entry. It returns io.EOF when there are no more entries
+func (r *Reader) Next() (Entry, error) {
+	var entry Entry
+	for {
+		line, err := r.lines.ReadBytes('\n')
+		start := r.offset
+		r.offset += int64(len(line))
+		if len(bytes.TrimSpace(line)) == 0 {
+			if err != nil {
+				return entry, err
+			}
+			continue
+		}
+		if jsonErr := json.Unmarshal(line, &entry); jsonErr != nil {
+			return entry, fmt.Errorf("invalid replay entry at offset %d: %s", start, jsonErr.Error())
+		}
+		r.indexEntry(entry, start)
+		return entry, nil
+	}
+}
+
+// SeekTurn moves the reader to the first entry whose turn is equal or greater than `turn`
+func (r *Reader) SeekTurn(turn int) error {
+	position := sort.Search(len(r.index), func(i int) bool {
+		return r.index[i].turn >= turn
+	})
+	if position < len(r.index) {
+		return r.seekOffset(r.index[position].offset)
+	}
+	// the turn was not indexed yet, so the reader continues from the last indexed entry
+	if len(r.index) > 0 {
+		if err := r.seekOffset(r.index[len(r.index)-1].offset); err != nil {
+			return err
+		}
+	}
+	for {
+		start := r.offset
+		entry, err := r.Next()
+		if err != nil {
+			return err
+		}
+		if entry.Kind != HeaderEntry && entry.Turn >= turn {
+			return r.seekOffset(start)
+		}
+	}
+}
+
+// indexEntry keeps the offset of the first entry of each turn
+func (r *Reader) indexEntry(entry Entry, offset int64) {
+	if entry.Kind == HeaderEntry || offset < r.indexed {
+		return
+	}
+	r.indexed = offset + 1
+	if len(r.index) == 0 || r.index[len(r.index)-1].turn < entry.Turn {
+		r.index = append(r.index, turnOffset{turn: entry.Turn, offset: offset})
+	}
+}
+
+func (r *Reader) seekOffset(offset int64) error {
+	if !r.compressed {
+		if _, err := r.source.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		r.lines = bufio.NewReader(r.source)
+		r.offset = offset
+		return nil
+	}
+	// a gzip stream cannot be seeked, so it is read again from the beginning
+	if err := r.rewind(); err != nil {
+		return err
+	}
+	skipped, err := io.CopyN(ioutil.Discard, r.lines, offset)
+	r.offset = skipped
+	return err
+}
+
+func (r *Reader) rewind() error {
+	if _, err := r.source.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	buffered := bufio.NewReader(r.source)
+	magic, _ := buffered.Peek(len(gzipMagic))
+	r.compressed = bytes.Equal(magic, gzipMagic)
+	r.offset = 0
+	if !r.compressed {
+		r.lines = buffered
+		return nil
+	}
+	decompressed, err := gzip.NewReader(buffered)
+	if err != nil {
+		return fmt.Errorf("fail on opening the gzip stream: %s", err.Error())
+	}
+	r.lines = bufio.NewReader(decompressed)
+	return nil
+}
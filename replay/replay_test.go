@@ -0,0 +1,207 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gorilla/websocket"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func newTestMessage(turn int) arena.GameMessage {
+	return arena.GameMessage{
+		Type: orders.ANNOUNCEMENT,
+		Snapshot: arena.Snapshot{
+			Turn:  turn,
+			State: arena.Listening,
+			Ball:  arena.Ball{Element: physics.Element{Coords: physics.Point{PosX: turn * 10, PosY: 5000}, Velocity: physics.NewZeroedVelocity(physics.East)}},
+		},
+	}
+}
+
+func newTestBatch(number arena.PlayerNumber) orders.Batch {
+	move := physics.NewZeroedVelocity(physics.North)
+	move.Speed = 50
+	return orders.Batch{Place: arena.HomeTeam, Number: number, Orders: []orders.Order{orders.NewMoveOrder(move), orders.NewCatchOrder()}}
+}
+
+func writeTestReplay(t *testing.T, compress bool, turns int) *bytes.Reader {
+	buffer := &bytes.Buffer{}
+	writer, err := NewWriter(buffer, Header{ProtocolVersion: "1.0", HomeTeam: "a", AwayTeam: "b", Rules: CurrentRules()}, compress)
+	assert.Nil(t, err)
+	for turn := 1; turn <= turns; turn++ {
+		assert.Nil(t, writer.WriteGameMessage(newTestMessage(turn)))
+		assert.Nil(t, writer.WriteOrders(turn, []orders.Batch{newTestBatch("2"), newTestBatch("3")}))
+	}
+	assert.Nil(t, writer.Close())
+	return bytes.NewReader(buffer.Bytes())
+}
+
+func TestWriterAndReader(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		reader, err := NewReader(writeTestReplay(t, compress, 5))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		header := reader.Header()
+		assert.Equal(t, FormatVersion, header.Version)
+		assert.Equal(t, "a", header.HomeTeam)
+		assert.Equal(t, CurrentRules(), header.Rules)
+		assert.False(t, header.RecordedAt.IsZero())
+
+		entry, err := reader.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, MessageEntry, entry.Kind)
+		assert.Equal(t, 1, entry.Turn)
+		msg, err := entry.GameMessage()
+		assert.Nil(t, err)
+		assert.Equal(t, orders.ANNOUNCEMENT, msg.Type)
+		assert.Equal(t, physics.Point{PosX: 10, PosY: 5000}, msg.Snapshot.Ball.Coords)
+
+		entry, err = reader.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, OrdersEntry, entry.Kind)
+		assert.Len(t, entry.Batches, 2)
+		assert.Equal(t, orders.MOVE, entry.Batches[0].Orders[0].Type)
+		assert.Equal(t, 50.0, entry.Batches[0].Orders[0].GetMoveOrderData().Velocity.Speed)
+		_, err = entry.GameMessage()
+		assert.NotNil(t, err)
+
+		count := 2
+		for _, err = reader.Next(); err == nil; _, err = reader.Next() {
+			count++
+		}
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, 10, count)
+	}
+}
+
+func TestReader_SeekTurn(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		reader, err := NewReader(writeTestReplay(t, compress, 10))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		assert.Nil(t, reader.SeekTurn(7))
+		entry, err := reader.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, 7, entry.Turn)
+		assert.Equal(t, MessageEntry, entry.Kind)
+
+		// backwards
+		assert.Nil(t, reader.SeekTurn(3))
+		entry, _ = reader.Next()
+		assert.Equal(t, 3, entry.Turn)
+		assert.Equal(t, MessageEntry, entry.Kind)
+
+		// already indexed
+		assert.Nil(t, reader.SeekTurn(6))
+		entry, _ = reader.Next()
+		assert.Equal(t, 6, entry.Turn)
+
+		assert.Equal(t, io.EOF, reader.SeekTurn(11))
+	}
+}
+
+func TestNewReader_InvalidHeader(t *testing.T) {
+	_, err := NewReader(bytes.NewReader([]byte(`{"kind":"message","turn":1}` + "\n")))
+	assert.NotNil(t, err)
+	_, err = NewReader(bytes.NewReader([]byte(`{"kind":"header","header":{"version":99}}` + "\n")))
+	assert.NotNil(t, err)
+	_, err = NewReader(bytes.NewReader(nil))
+	assert.NotNil(t, err)
+}
+
+type fakeTalker struct {
+	sent   [][]byte
+	listen chan []byte
+}
+
+func (f *fakeTalker) Connect(mainCtx context.Context, url url.URL, playerSpec arena.PlayerSpecifications) (context.Context, error) {
+	return mainCtx, nil
+}
+
+func (f *fakeTalker) Send(data []byte) error {
+	f.sent = append(f.sent, data)
+	return nil
+}
+
+func (f *fakeTalker) Listen() <-chan []byte {
+	return f.listen
+}
+
+func (f *fakeTalker) ListenInterruption() <-chan *websocket.CloseError {
+	return nil
+}
+
+func (f *fakeTalker) Close() {}
+
+func (f *fakeTalker) Profile() arena.Profile {
+	return arena.Profile{}
+}
+
+func (f *fakeTalker) Field() arena.Field {
+	return arena.NewField()
+}
+
+func TestTap(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	writer, err := NewWriter(buffer, Header{}, false)
+	assert.Nil(t, err)
+	inner := &fakeTalker{listen: make(chan []byte, 1)}
+	var errs []error
+	talker := Tap(inner, writer, arena.AwayTeam, func(err error) { errs = append(errs, err) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err = talker.Connect(ctx, url.URL{}, arena.PlayerSpecifications{Number: "4"})
+	assert.Nil(t, err)
+
+	announcement, _ := json.Marshal(newTestMessage(12))
+	inner.listen <- announcement
+	select {
+	case msg := <-talker.Listen():
+		assert.Equal(t, announcement, msg)
+	case <-time.After(time.Second):
+		assert.Fail(t, "the message was not forwarded")
+	}
+
+	order, _ := json.Marshal(orders.PlayerMessage{Type: orders.ORDER, Orders: []orders.Order{orders.NewCatchOrder()}})
+	assert.Nil(t, talker.Send(order))
+	assert.Equal(t, [][]byte{order}, inner.sent)
+	assert.Nil(t, writer.Close())
+	assert.Empty(t, errs)
+
+	reader, err := NewReader(bytes.NewReader(buffer.Bytes()))
+	assert.Nil(t, err)
+	entry, _ := reader.Next()
+	assert.Equal(t, MessageEntry, entry.Kind)
+	assert.Equal(t, 12, entry.Turn)
+	entry, _ = reader.Next()
+	assert.Equal(t, OrdersEntry, entry.Kind)
+	assert.Equal(t, 12, entry.Turn)
+	assert.Equal(t, []orders.Batch{{Place: arena.AwayTeam, Number: "4", Orders: []orders.Order{orders.NewCatchOrder()}}}, entry.Batches)
+}
+
+func TestHeader_MatchRules(t *testing.T) {
+	rules, err := Header{}.MatchRules()
+	assert.Nil(t, err)
+	assert.Equal(t, CurrentRules(), rules)
+
+	custom := CurrentRules()
+	custom.PlayerMaxSpeed = 200
+	rules, err = Header{Rules: custom}.MatchRules()
+	assert.Nil(t, err)
+	assert.Equal(t, custom, rules)
+
+	custom.FieldWidth = -1
+	_, err = Header{Rules: custom}.MatchRules()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,84 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/talk"
+	"net/url"
+)
+
+// tappedTalker records the traffic of a talker while forwarding it
+type tappedTalker struct {
+	talk.Talker
+	writer  *Writer
+	place   arena.TeamPlace
+	number  arena.PlayerNumber
+	listen  chan []byte
+	onError func(error)
+}
+
+// Tap wraps a talker recording every message received from the game server and every order sent by the player.
+// The team place identifies the player team in the recorded orders, since it is not part of the player specs.
+// Recording errors do not interrupt the communication, they are passed to the `onError` function (that may be nil).
+func Tap(talker talk.Talker, writer *Writer, place arena.TeamPlace, onError func(error)) talk.Talker {
+	if onError == nil {
+		onError = func(error) {}
+	}
+	return &tappedTalker{
+		Talker:  talker,
+		writer:  writer,
+		place:   place,
+		listen:  make(chan []byte, 1),
+		onError: onError,
+	}
+}
+
+// Connect opens the connection and starts recording the messages sent by the game server
+func (t *tappedTalker) Connect(mainCtx context.Context, url url.URL, playerSpec arena.PlayerSpecifications) (context.Context, error) {
+	ctx, err := t.Talker.Connect(mainCtx, url, playerSpec)
+	if err != nil {
+		return ctx, err
+	}
+	t.number = playerSpec.Number
+	go t.keepRecording(ctx)
+	return ctx, nil
+}
+
+// Listen send a new message when the game server send one
+func (t *tappedTalker) Listen() <-chan []byte {
+	return t.listen
+}
+
+// Send records the orders in the message before sending it to the game server
+func (t *tappedTalker) Send(data []byte) error {
+	var msg orders.PlayerMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.onError(err)
+	} else if msg.Type == orders.ORDER {
+		batch := orders.Batch{Place: t.place, Number: t.number, Orders: msg.Orders}
+		if err := t.writer.WriteOrders(t.writer.LastTurn(), []orders.Batch{batch}); err != nil {
+			t.onError(err)
+		}
+	}
+	return t.Talker.Send(data)
+}
+
+func (t *tappedTalker) keepRecording(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-t.Talker.Listen():
+			if err := t.writer.WriteMessage(msg); err != nil {
+				t.onError(err)
+			}
+			select {
+			case t.listen <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
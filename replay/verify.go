@@ -0,0 +1,167 @@
+package replay
+
+import (
+	"fmt"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/sim"
+	"io"
+	"math"
+)
+
+// Tolerance defines how different the simulated values may be from the recorded ones
+type Tolerance struct {
+	// Position is the max distance between the simulated and the recorded positions
+	Position float64
+	// Speed is the max difference between the simulated and the recorded speeds
+	Speed float64
+	// Angle is the max difference (in degrees) between the simulated and the recorded directions
+	Angle float64
+}
+
+// DefaultTolerance accepts the differences caused by rounding the positions to integers
+var DefaultTolerance = Tolerance{Position: 1.5, Speed: 0.01, Angle: 0.5}
+
+// Divergence describes the first difference found between a replay and its simulation
+type Divergence struct {
+	// Turn is the turn of the divergent announcement
+	Turn int
+	// Element identifies the element with different values (e.g. "ball", "home/7", "away team")
+	Element string
+	// Attribute is the name of the divergent attribute (e.g. "position", "speed", "score")
+	Attribute string
+	// Recorded is the value sent by the game server
+	Recorded string
+	// Simulated is the value found by the local simulation
+	Simulated string
+}
+
+// String returns the string representation of the divergence
+func (d *Divergence) String() string {
+	return fmt.Sprintf("turn %d: %s %s differs (recorded %s, simulated %s)", d.Turn, d.Element, d.Attribute, d.Recorded, d.Simulated)
+}
+
+// Verify re-simulates the recorded match: starting from the first announcement, it applies the recorded orders turn
+// by turn and compares the simulated state with each recorded announcement. It returns the first divergence found,
+// or nil when the whole replay matches the local simulation.
+// The match is simulated with the rules of the replay header, or with the default rules when the header has none,
+// and with the fixed-point physics when the header is marked as deterministic.
+// The order the server processed the players orders is not recorded, so the seed is used by the simulator to decide
+// it (see sim.Config).
+func Verify(reader *Reader, tolerance Tolerance, seed int64) (*Divergence, error) {
+	header := reader.Header()
+	rules, err := header.MatchRules()
+	if err != nil {
+		return nil, fmt.Errorf("the replay rules cannot be simulated: %s", err)
+	}
+	config := sim.Config{Rules: rules, Seed: seed, MaxTurns: math.MaxInt32, Deterministic: header.Deterministic}
+	var simulator *sim.Simulator
+	var pending []orders.Batch
+	for {
+		entry, err := reader.Next()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch entry.Kind {
+		case OrdersEntry:
+			pending = append(pending, entry.Batches...)
+		case MessageEntry:
+			msg, err := entry.GameMessage()
+			if err != nil {
+				return nil, err
+			}
+			if msg.Type != orders.ANNOUNCEMENT {
+				continue
+			}
+			if simulator == nil {
+				simulator = sim.NewFromSnapshotWithConfig(msg.Snapshot, config)
+				pending = nil
+				continue
+			}
+			for simulator.Turn() < msg.Snapshot.Turn {
+				simulator.Step(pending)
+				pending = nil
+			}
+			simulated := simulator.Snapshot()
+			if divergence := Compare(msg.Snapshot, simulated, tolerance); divergence != nil {
+				return divergence, nil
+			}
+		}
+	}
+}
+
+// Compare finds the first difference between two snapshots of the same turn considering the tolerance
+func Compare(recorded, simulated arena.Snapshot, tolerance Tolerance) *Divergence {
+	turn := recorded.Turn
+	for _, place := range []arena.TeamPlace{arena.HomeTeam, arena.AwayTeam} {
+		recordedTeam, simulatedTeam := recorded.Team(place), simulated.Team(place)
+		if recordedTeam.Score != simulatedTeam.Score {
+			return &Divergence{Turn: turn, Element: fmt.Sprintf("%s team", place), Attribute: "score",
+				Recorded: fmt.Sprint(recordedTeam.Score), Simulated: fmt.Sprint(simulatedTeam.Score)}
+		}
+	}
+	if divergence := compareElement(turn, "ball", recorded.Ball.Element, simulated.Ball.Element, tolerance); divergence != nil {
+		return divergence
+	}
+	if holder, simulatedHolder := holderName(recorded.Ball.Holder), holderName(simulated.Ball.Holder); holder != simulatedHolder {
+		return &Divergence{Turn: turn, Element: "ball", Attribute: "holder", Recorded: holder, Simulated: simulatedHolder}
+	}
+	for _, player := range recorded.Players() {
+		name := fmt.Sprintf("%s/%s", player.TeamPlace, player.Number)
+		simulatedPlayer := simulated.Player(player.TeamPlace, player.Number)
+		if simulatedPlayer == nil {
+			return &Divergence{Turn: turn, Element: name, Attribute: "presence", Recorded: "present", Simulated: "missing"}
+		}
+		if divergence := compareElement(turn, name, player.Element, simulatedPlayer.Element, tolerance); divergence != nil {
+			return divergence
+		}
+	}
+	return nil
+}
+
+func compareElement(turn int, name string, recorded, simulated physics.Element, tolerance Tolerance) *Divergence {
+	if recorded.Coords.DistanceTo(simulated.Coords) > tolerance.Position {
+		return &Divergence{Turn: turn, Element: name, Attribute: "position",
+			Recorded: recorded.Coords.String(), Simulated: simulated.Coords.String()}
+	}
+	if math.Abs(recorded.Velocity.Speed-simulated.Velocity.Speed) > tolerance.Speed {
+		return &Divergence{Turn: turn, Element: name, Attribute: "speed",
+			Recorded: fmt.Sprintf("%.2f", recorded.Velocity.Speed), Simulated: fmt.Sprintf("%.2f", simulated.Velocity.Speed)}
+	}
+	// the direction does not matter when the element is stopped
+	if recorded.Velocity.Speed == 0 || recorded.Velocity.Direction == nil || simulated.Velocity.Direction == nil {
+		return nil
+	}
+	if difference := directionDifference(recorded.Velocity.Direction, simulated.Velocity.Direction); math.IsNaN(difference) || difference > tolerance.Angle {
+		return &Divergence{Turn: turn, Element: name, Attribute: "direction",
+			Recorded: fmt.Sprintf("%.2f°", recorded.Velocity.Direction.AngleDegrees()), Simulated: fmt.Sprintf("%.2f°", simulated.Velocity.Direction.AngleDegrees())}
+	}
+	return nil
+}
+
+// directionDifference returns the angle (in degrees) between the directions. It is NaN when a direction has no length.
+func directionDifference(a, b *physics.Vector) float64 {
+	if a.Length() == 0 || b.Length() == 0 {
+		return math.NaN()
+	}
+	angle := math.Abs(a.AngleWith(b))
+	if math.IsNaN(angle) {
+		// the rounding may put the cosine out of [-1, 1] when the directions are (almost) parallel
+		angle = math.Mod(math.Abs(a.AngleDegrees()-b.AngleDegrees()), 360)
+		if angle > 180 {
+			angle = 360 - angle
+		}
+	}
+	return angle
+}
+
+func holderName(holder *arena.Player) string {
+	if holder == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%s/%s", holder.TeamPlace, holder.Number)
+}
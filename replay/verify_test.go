@@ -0,0 +1,193 @@
+package replay
+
+import (
+	"bytes"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/sim"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// recordMatch records a simulated match where every player runs towards random points trying to catch and kick the ball
+func recordMatch(t *testing.T, seed int64, turns int, deterministic bool, tamper func(turn int, msg *arena.GameMessage)) *bytes.Reader {
+	buffer := &bytes.Buffer{}
+	// the recording time is fixed, so the same match is recorded with the same bytes
+	header := Header{Rules: CurrentRules(), Deterministic: deterministic, RecordedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	writer, err := NewWriter(buffer, header, false)
+	assert.Nil(t, err)
+	simulator := sim.New(sim.Config{Seed: seed, MaxTurns: turns, Deterministic: deterministic})
+	r := rand.New(rand.NewSource(seed))
+	assert.Nil(t, writer.WriteGameMessage(arena.GameMessage{Type: orders.ANNOUNCEMENT, Snapshot: simulator.Snapshot()}))
+	for !simulator.IsOver() {
+		snapshot := simulator.Snapshot()
+		var batches []orders.Batch
+		for _, player := range snapshot.Players() {
+			target := physics.Point{PosX: r.Intn(units.FieldWidth), PosY: r.Intn(units.FieldHeight)}
+			direction, err := physics.NewVector(player.Coords, target)
+			if err != nil {
+				continue
+			}
+			velocity := physics.NewZeroedVelocity(*direction.Normalize())
+			velocity.Speed = units.PlayerMaxSpeed
+			kick := velocity.Copy()
+			kick.Speed = units.BallMaxSpeed
+			batches = append(batches, orders.Batch{Place: player.TeamPlace, Number: player.Number, Orders: []orders.Order{
+				orders.NewMoveOrder(velocity), orders.NewCatchOrder(), orders.NewKickOrder(kick),
+			}})
+		}
+		assert.Nil(t, writer.WriteOrders(snapshot.Turn, batches))
+		simulator.Step(batches)
+
+		msg := arena.GameMessage{Type: orders.ANNOUNCEMENT, Snapshot: simulator.Snapshot()}
+		if tamper != nil {
+			tamper(msg.Snapshot.Turn, &msg)
+		}
+		assert.Nil(t, writer.WriteGameMessage(msg))
+	}
+	assert.Nil(t, writer.Close())
+	return bytes.NewReader(buffer.Bytes())
+}
+
+func TestVerify_Matching(t *testing.T) {
+	reader, err := NewReader(recordMatch(t, 3, 200, false, nil))
+	assert.Nil(t, err)
+	divergence, err := Verify(reader, DefaultTolerance, 3)
+	assert.Nil(t, err)
+	assert.Nil(t, divergence)
+}
+
+func TestVerify_Deterministic(t *testing.T) {
+	first, err := ioutil.ReadAll(recordMatch(t, 7, 300, true, nil))
+	assert.Nil(t, err)
+	second, err := ioutil.ReadAll(recordMatch(t, 7, 300, true, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, first, second, "the deterministic matches must be byte-identical")
+
+	reader, err := NewReader(bytes.NewReader(first))
+	assert.Nil(t, err)
+	assert.True(t, reader.Header().Deterministic)
+	// the fixed-point simulation needs no tolerance
+	divergence, err := Verify(reader, Tolerance{}, 7)
+	assert.Nil(t, err)
+	assert.Nil(t, divergence)
+}
+
+func TestVerify_Fixture(t *testing.T) {
+	// the fixture was written by hand with rules where the players are faster and the ball decelerates faster
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "custom_rules.replay"))
+	assert.Nil(t, err)
+	reader, err := NewReader(bytes.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, 200.0, reader.Header().Rules.PlayerMaxSpeed)
+	divergence, err := Verify(reader, DefaultTolerance, 1)
+	assert.Nil(t, err)
+	assert.Nil(t, divergence)
+
+	// the same match does not follow the default player speed
+	defaultSpeed := bytes.Replace(data, []byte(`"player_max_speed":200`), []byte(`"player_max_speed":100`), 1)
+	reader, err = NewReader(bytes.NewReader(defaultSpeed))
+	assert.Nil(t, err)
+	divergence, err = Verify(reader, DefaultTolerance, 1)
+	assert.Nil(t, err)
+	if assert.NotNil(t, divergence) {
+		assert.Equal(t, 1, divergence.Turn)
+		assert.Equal(t, "home/7", divergence.Element)
+	}
+
+	// rules that cannot be simulated are reported
+	invalid := bytes.Replace(data, []byte(`"ball_deceleration":20`), []byte(`"ball_deceleration":0`), 1)
+	reader, err = NewReader(bytes.NewReader(invalid))
+	assert.Nil(t, err)
+	_, err = Verify(reader, DefaultTolerance, 1)
+	assert.NotNil(t, err)
+}
+
+func TestVerify_Divergence(t *testing.T) {
+	movePlayer := func(turn int, msg *arena.GameMessage) {
+		if turn == 120 {
+			msg.Snapshot.Player(arena.AwayTeam, "8").Coords.PosX += 2
+		}
+	}
+	table := map[string]struct {
+		tamper    func(turn int, msg *arena.GameMessage)
+		tolerance Tolerance
+		element   string
+		attribute string
+	}{
+		"player position":  {movePlayer, DefaultTolerance, "away/8", "position"},
+		"larger tolerance": {movePlayer, Tolerance{Position: 3, Speed: 0.01, Angle: 0.5}, "", ""},
+		"ball speed": {func(turn int, msg *arena.GameMessage) {
+			if turn == 120 {
+				msg.Snapshot.Ball.Velocity.Speed += 1
+			}
+		}, DefaultTolerance, "ball", "speed"},
+		"score": {func(turn int, msg *arena.GameMessage) {
+			if turn == 120 {
+				msg.Snapshot.HomeTeam.Score += 1
+			}
+		}, DefaultTolerance, "home team", "score"},
+	}
+	for title, set := range table {
+		reader, err := NewReader(recordMatch(t, 3, 200, false, set.tamper))
+		assert.Nil(t, err, title)
+		divergence, err := Verify(reader, set.tolerance, 3)
+		assert.Nil(t, err, title)
+		if set.element == "" {
+			assert.Nil(t, divergence, title)
+			continue
+		}
+		if assert.NotNil(t, divergence, title) {
+			assert.Equal(t, 120, divergence.Turn, title)
+			assert.Equal(t, set.element, divergence.Element, title)
+			assert.Equal(t, set.attribute, divergence.Attribute, title)
+			assert.Contains(t, divergence.String(), "turn 120: "+set.element+" "+set.attribute+" differs", title)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	simulator := sim.New(sim.Config{})
+	recorded := simulator.Snapshot()
+	simulated := simulator.Snapshot()
+	assert.Nil(t, Compare(recorded, simulated, DefaultTolerance))
+
+	simulated.AwayTeam.Score = 1
+	divergence := Compare(recorded, simulated, DefaultTolerance)
+	if assert.NotNil(t, divergence) {
+		assert.Equal(t, "score", divergence.Attribute)
+		assert.Equal(t, "away team", divergence.Element)
+	}
+
+	simulated = simulator.Snapshot()
+	simulated.Ball.Velocity.Speed = 10
+	divergence = Compare(recorded, simulated, DefaultTolerance)
+	if assert.NotNil(t, divergence) {
+		assert.Equal(t, "speed", divergence.Attribute)
+		assert.Equal(t, "ball", divergence.Element)
+	}
+
+	simulated = simulator.Snapshot()
+	simulated.Ball.Holder = simulated.Player(arena.HomeTeam, "3")
+	divergence = Compare(recorded, simulated, DefaultTolerance)
+	if assert.NotNil(t, divergence) {
+		assert.Equal(t, "holder", divergence.Attribute)
+		assert.Equal(t, "home/3", divergence.Simulated)
+	}
+
+	// a direction without length cannot be compared
+	recorded.Ball.Velocity.Speed = 10
+	recorded.Ball.Velocity.Direction = &physics.Vector{}
+	simulated = simulator.Snapshot()
+	simulated.Ball.Velocity.Speed = 10
+	divergence = Compare(recorded, simulated, DefaultTolerance)
+	if assert.NotNil(t, divergence) {
+		assert.Equal(t, "direction", divergence.Attribute)
+	}
+}
@@ -0,0 +1,113 @@
+package replay
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"io"
+	"sync"
+	"time"
+)
+
+// Writer records a match in the replay format: one JSON entry per line, optionally compressed with gzip.
+// It is safe to use the writer from several goroutines.
+type Writer struct {
+	mutex    sync.Mutex
+	buffer   *bufio.Writer
+	gzip     *gzip.Writer
+	encoder  *json.Encoder
+	lastTurn int
+}
+
+// NewWriter creates a writer and records the header. The version and recording time are filled when not set.
+func NewWriter(w io.Writer, header Header, compress bool) (*Writer, error) {
+	writer := &Writer{}
+	if compress {
+		writer.gzip = gzip.NewWriter(w)
+		w = writer.gzip
+	}
+	writer.buffer = bufio.NewWriter(w)
+	writer.encoder = json.NewEncoder(writer.buffer)
+
+	if header.Version == 0 {
+		header.Version = FormatVersion
+	}
+	if header.RecordedAt.IsZero() {
+		header.RecordedAt = time.Now().UTC()
+	}
+	if err := writer.write(Entry{Kind: HeaderEntry, Header: &header}); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// WriteMessage records a raw message sent by the game server. The turn is taken from the message snapshot.
+func (w *Writer) WriteMessage(raw []byte) error {
+	var msg arena.GameMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return fmt.Errorf("fail on decoding the game message: %s", err.Error())
+	}
+	return w.write(Entry{Kind: MessageEntry, Turn: msg.Snapshot.Turn, Message: json.RawMessage(raw)})
+}
+
+// WriteGameMessage records a message sent by the game server
+func (w *Writer) WriteGameMessage(msg arena.GameMessage) error {
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("fail on encoding the game message: %s", err.Error())
+	}
+	return w.write(Entry{Kind: MessageEntry, Turn: msg.Snapshot.Turn, Message: json.RawMessage(raw)})
+}
+
+// WriteOrders records the orders sent by the players in a turn
+func (w *Writer) WriteOrders(turn int, batches []orders.Batch) error {
+	return w.write(Entry{Kind: OrdersEntry, Turn: turn, Batches: batches})
+}
+
+// LastTurn returns the turn of the last recorded message
+func (w *Writer) LastTurn() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.lastTurn
+}
+
+// Flush writes the buffered entries to the underlying writer
+func (w *Writer) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if err := w.buffer.Flush(); err != nil {
+		return err
+	}
+	if w.gzip != nil {
+		return w.gzip.Flush()
+	}
+	return nil
+}
+
+// Close flushes the entries and finishes the gzip stream. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if err := w.buffer.Flush(); err != nil {
+		return err
+	}
+	if w.gzip != nil {
+		return w.gzip.Close()
+	}
+	return nil
+}
+
+func (w *Writer) write(entry Entry) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if entry.Kind == MessageEntry {
+		w.lastTurn = entry.Turn
+	}
+	if err := w.encoder.Encode(entry); err != nil {
+		return fmt.Errorf("fail on writing the replay entry: %s", err.Error())
+	}
+	return nil
+}
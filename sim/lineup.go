@@ -0,0 +1,51 @@
+package sim
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+)
+
+// Lineup maps each player number to its initial position
+type Lineup map[arena.PlayerNumber]physics.Point
+
+// DefaultLineup is the initial position of the players in the home team frame (attacking towards +X). The away team
+// positions are mirrored.
+var DefaultLineup = Lineup{
+	"1":  {PosX: units.PlayerSize, PosY: units.FieldHeight / 2},
+	"2":  {PosX: 30 * units.BaseUnit, PosY: 20 * units.BaseUnit},
+	"3":  {PosX: 30 * units.BaseUnit, PosY: 40 * units.BaseUnit},
+	"4":  {PosX: 30 * units.BaseUnit, PosY: 60 * units.BaseUnit},
+	"5":  {PosX: 30 * units.BaseUnit, PosY: 80 * units.BaseUnit},
+	"6":  {PosX: 55 * units.BaseUnit, PosY: 20 * units.BaseUnit},
+	"7":  {PosX: 55 * units.BaseUnit, PosY: 40 * units.BaseUnit},
+	"8":  {PosX: 55 * units.BaseUnit, PosY: 60 * units.BaseUnit},
+	"9":  {PosX: 55 * units.BaseUnit, PosY: 80 * units.BaseUnit},
+	"10": {PosX: 80 * units.BaseUnit, PosY: 35 * units.BaseUnit},
+	"11": {PosX: 80 * units.BaseUnit, PosY: 65 * units.BaseUnit},
+}
+
+// playerNumbers lists the numbers in a stable order
+var playerNumbers = []arena.PlayerNumber{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}
+
+// newTeam creates the team players in the lineup positions. The lineup is in the home team frame
+func (s *Simulator) newTeam(place arena.TeamPlace, name string, lineup Lineup) arena.Team {
+	team := arena.Team{Place: place, Name: name}
+	mirror := arena.Mirror(place)
+	for _, number := range playerNumbers {
+		position, ok := lineup[number]
+		if !ok {
+			continue
+		}
+		team.Players = append(team.Players, arena.Player{
+			Element: physics.Element{
+				Size:     s.rules.PlayerSize,
+				Coords:   mirror.PointIn(s.field, position),
+				Velocity: mirror.Velocity(physics.NewZeroedVelocity(physics.East)),
+			},
+			Number:    number,
+			TeamPlace: place,
+		})
+	}
+	return team
+}
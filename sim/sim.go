@@ -0,0 +1,411 @@
+package sim
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/physics/fixed"
+	"github.com/lugobots/arena/units"
+	"math"
+	"math/rand"
+)
+
+// DefaultMaxTurns is the number of turns of a match when no other value is specified
+const DefaultMaxTurns = 3000
+
+// Config sets up a new simulation
+type Config struct {
+	// Seed feeds the random source used to decide the order the players orders are applied
+	Seed int64
+	// MaxTurns is the number of turns of the match. DefaultMaxTurns is used when it is zero
+	MaxTurns int
+	// HomeTeamName is the name of the home team
+	HomeTeamName string
+	// AwayTeamName is the name of the away team
+	AwayTeamName string
+	// HomeLineup is the initial position of the home team players. DefaultLineup is used when it is nil
+	HomeLineup Lineup
+	// AwayLineup is the initial position of the away team players, in the away team frame (attacking towards +X,
+	// see arena.Mirror). DefaultLineup is used when it is nil
+	AwayLineup Lineup
+	// Rules are the game rules followed by the simulation. The default rules are used when it is the zero value
+	Rules units.Rules
+	// Deterministic moves the players and the ball with the fixed-point physics (see the physics/fixed package), so
+	// the same match produces bit-identical snapshots in any platform and Go version
+	Deterministic bool
+}
+
+// Result tells what happened during a turn
+type Result struct {
+	// Turn is the turn that was played
+	Turn int
+	// Goal is set when a team scored in this turn
+	Goal *arena.GoalCrossing
+	// ScoredBy is the team that scored the goal
+	ScoredBy arena.TeamPlace
+	// AutoKicked is true when the ball was kicked towards the field center after staying too long in a goal zone
+	AutoKicked bool
+	// Over is true when the match is over
+	Over bool
+}
+
+type playerKey struct {
+	place  arena.TeamPlace
+	number arena.PlayerNumber
+}
+
+// Simulator holds a full game state and advances it turn by turn applying the players orders following the game
+// rules. The simulation is deterministic: the same seed and the same orders always produce the same match. The float
+// math may still differ between platforms, unless the fixed-point physics is used (see Config.Deterministic).
+// Players do not collide with each other, and the only interactions with the ball happen through the orders.
+type Simulator struct {
+	state      arena.Snapshot
+	maxTurns   int
+	random     *rand.Rand
+	rules      units.Rules
+	engine     physics.Engine
+	fixed      *fixed.Engine
+	field      arena.Field
+	lineups    map[arena.TeamPlace]Lineup
+	holder     *playerKey
+	jumping    map[playerKey]int
+	inGoalZone int
+}
+
+// New creates a simulator with the players in their initial positions and the ball in the field center
+func New(config Config) *Simulator {
+	s := newSimulator(config)
+	s.lineups[arena.HomeTeam] = config.HomeLineup
+	s.lineups[arena.AwayTeam] = config.AwayLineup
+	for place, lineup := range s.lineups {
+		if lineup == nil {
+			s.lineups[place] = DefaultLineup
+		}
+	}
+	s.state = arena.Snapshot{
+		State:    arena.Listening,
+		HomeTeam: s.newTeam(arena.HomeTeam, config.HomeTeamName, s.lineups[arena.HomeTeam]),
+		AwayTeam: s.newTeam(arena.AwayTeam, config.AwayTeamName, s.lineups[arena.AwayTeam]),
+	}
+	s.resetBall()
+	return s
+}
+
+// NewFromSnapshot creates a simulator that continues a game from the snapshot. The players positions in the snapshot
+// are used as their positions after each goal.
+func NewFromSnapshot(snapshot arena.Snapshot, seed int64, maxTurns int) *Simulator {
+	return NewFromSnapshotWithRules(snapshot, units.DefaultRules(), seed, maxTurns)
+}
+
+// NewFromSnapshotWithRules creates a simulator that continues a game played with the rules from the snapshot, see
+// NewFromSnapshot
+func NewFromSnapshotWithRules(snapshot arena.Snapshot, rules units.Rules, seed int64, maxTurns int) *Simulator {
+	return NewFromSnapshotWithConfig(snapshot, Config{Rules: rules, Seed: seed, MaxTurns: maxTurns})
+}
+
+// NewFromSnapshotWithConfig creates a simulator that continues a game from the snapshot with the seed, the max turns,
+// the rules and the mode of the config, see NewFromSnapshot. The team names and the lineups of the config are not
+// used, they come from the snapshot.
+func NewFromSnapshotWithConfig(snapshot arena.Snapshot, config Config) *Simulator {
+	s := newSimulator(config)
+	s.state = snapshot.Copy()
+	for _, place := range []arena.TeamPlace{arena.HomeTeam, arena.AwayTeam} {
+		lineup := Lineup{}
+		mirror := arena.Mirror(place)
+		for _, player := range s.state.Team(place).Players {
+			lineup[player.Number] = mirror.PointIn(s.field, player.Coords)
+		}
+		s.lineups[place] = lineup
+	}
+	if holder := snapshot.Ball.Holder; holder != nil {
+		s.holder = &playerKey{place: holder.TeamPlace, number: holder.Number}
+		// the holder is tracked by the simulator, and added to the snapshots when they are copied
+		s.state.Ball.Holder = nil
+	}
+	if s.state.State == "" {
+		s.state.State = arena.Listening
+	}
+	return s
+}
+
+func newSimulator(config Config) *Simulator {
+	maxTurns := config.MaxTurns
+	if maxTurns <= 0 {
+		maxTurns = DefaultMaxTurns
+	}
+	rules := config.Rules
+	if rules == (units.Rules{}) {
+		rules = units.DefaultRules()
+	}
+	s := &Simulator{
+		maxTurns: maxTurns,
+		random:   rand.New(rand.NewSource(config.Seed)),
+		rules:    rules,
+		engine:   physics.NewEngine(rules),
+		field:    arena.NewFieldFromRules(rules),
+		lineups:  map[arena.TeamPlace]Lineup{},
+		jumping:  map[playerKey]int{},
+	}
+	if config.Deterministic {
+		engine := fixed.NewEngine(rules)
+		s.fixed = &engine
+	}
+	return s
+}
+
+// IsDeterministic tells if the simulator uses the fixed-point physics, see Config
+func (s *Simulator) IsDeterministic() bool {
+	return s.fixed != nil
+}
+
+// Snapshot returns a copy of the current game state
+func (s *Simulator) Snapshot() arena.Snapshot {
+	snapshot := s.state.Copy()
+	if s.holder != nil {
+		holder := snapshot.Player(s.holder.place, s.holder.number).Copy()
+		snapshot.Ball.Holder = &holder
+	}
+	return snapshot
+}
+
+// Turn returns the number of turns already played
+func (s *Simulator) Turn() int {
+	return s.state.Turn
+}
+
+// IsOver returns true when the match is over
+func (s *Simulator) IsOver() bool {
+	return s.state.State == arena.Over
+}
+
+// Step plays a turn applying the orders sent by the players. Orders of unknown players, or orders that the player is
+// not allowed to execute (e.g. a kick by a player that does not hold the ball), are ignored.
+func (s *Simulator) Step(batches []orders.Batch) Result {
+	if s.IsOver() {
+		return Result{Turn: s.state.Turn, Over: true}
+	}
+	s.state.State = arena.Listening
+	s.state.Turn++
+	result := Result{Turn: s.state.Turn}
+
+	// the server does not guarantee the order the players orders are processed
+	shuffled := append([]orders.Batch{}, batches...)
+	s.random.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	for _, batch := range shuffled {
+		player := s.state.Player(batch.Place, batch.Number)
+		if player == nil {
+			continue
+		}
+		for _, order := range batch.Orders {
+			s.applyOrder(player, order)
+		}
+	}
+
+	s.movePlayers()
+	ballFrom := s.state.Ball.Coords
+	s.moveBall()
+
+	if crossing, ok := s.findGoalCrossing(ballFrom, s.state.Ball.Coords); ok && crossing.Scored {
+		result.Goal = &crossing
+		result.ScoredBy = crossing.Goal.Place.Opponent()
+		s.state.Team(result.ScoredBy).Score++
+		s.state.State = arena.Results
+		s.resetPositions()
+	} else {
+		result.AutoKicked = s.checkGoalZoneTime()
+	}
+
+	if s.state.Turn >= s.maxTurns {
+		s.state.State = arena.Over
+		result.Over = true
+	}
+	return result
+}
+
+func (s *Simulator) applyOrder(player *arena.Player, order orders.Order) {
+	key := playerKey{place: player.TeamPlace, number: player.Number}
+	switch order.Type {
+	case orders.MOVE:
+		if s.jumping[key] > 0 {
+			return
+		}
+		player.Velocity = s.limitSpeed(order.GetMoveOrderData().Velocity, s.rules.PlayerMaxSpeed)
+	case orders.JUMP:
+		if player.Number != arena.GoalkeeperNumber || s.jumping[key] > 0 {
+			return
+		}
+		player.Velocity = s.limitSpeed(order.GetJumpOrderData().Velocity, s.rules.GoalKeeperJumpSpeed)
+		s.jumping[key] = s.rules.GoalKeeperJumpDuration
+	case orders.CATCH:
+		if s.holder != nil && s.holder.place == player.TeamPlace {
+			return
+		}
+		if s.touches(player.Element, s.state.Ball.Element) {
+			s.holder = &key
+			s.inGoalZone = 0
+		}
+	case orders.KICK:
+		if s.holder == nil || *s.holder != key {
+			return
+		}
+		kick := order.GetKickOrderData().Velocity
+		if kick.Direction == nil {
+			return
+		}
+		s.state.Ball.Velocity = s.limitSpeed(s.addVelocities(player.Velocity, kick), s.rules.BallMaxSpeed)
+		s.holder = nil
+	}
+}
+
+func (s *Simulator) movePlayers() {
+	for _, team := range []*arena.Team{&s.state.HomeTeam, &s.state.AwayTeam} {
+		for i := range team.Players {
+			player := &team.Players[i]
+			key := playerKey{place: player.TeamPlace, number: player.Number}
+			target := s.playerTarget(player.Element)
+			// players cannot get into the opponent goal zone
+			if !s.field.IsInGoalZone(player.TeamPlace.Opponent(), target) {
+				player.Coords = target
+			}
+			if remaining := s.jumping[key]; remaining > 0 {
+				s.jumping[key] = remaining - 1
+				if remaining == 1 {
+					player.Velocity.Speed = 0
+				}
+			}
+		}
+	}
+}
+
+func (s *Simulator) moveBall() {
+	ball := &s.state.Ball
+	if s.holder != nil {
+		holder := s.state.Player(s.holder.place, s.holder.number)
+		ball.Coords = holder.Coords
+		ball.Velocity = holder.Velocity.Copy()
+		return
+	}
+	if ball.Velocity.Speed == 0 {
+		return
+	}
+	if s.fixed != nil {
+		element := fixed.FromElement(ball.Element)
+		s.fixed.StepBall(&element)
+		ball.Element = element.Physics()
+		return
+	}
+	ball.Coords, ball.Velocity, _ = s.engine.ReflectOnBorders(ball.Coords, ball.Velocity, ball.Size)
+	ball.Velocity.Speed -= s.rules.BallDeceleration
+	if ball.Velocity.Speed <= s.rules.BallMinSpeed {
+		ball.Velocity.Speed = 0
+	}
+}
+
+// checkGoalZoneTime counts the turns the ball stays in a goal zone and kicks it when it stays there for too long
+func (s *Simulator) checkGoalZoneTime() bool {
+	ball := &s.state.Ball
+	if !s.field.IsInGoalZone(arena.HomeTeam, ball.Coords) && !s.field.IsInGoalZone(arena.AwayTeam, ball.Coords) {
+		s.inGoalZone = 0
+		return false
+	}
+	s.inGoalZone++
+	if s.inGoalZone <= s.rules.BallTimeInGoalZone {
+		return false
+	}
+	s.inGoalZone = 0
+	s.holder = nil
+	if ball.Coords == s.field.Center {
+		return false
+	}
+	if s.fixed != nil {
+		ball.Velocity = fixed.NewVelocityTo(ball.Coords, s.field.Center, fixed.FromFloat(s.rules.BallMaxSpeed)).Physics()
+	} else {
+		ball.Velocity = physics.NewVelocityTo(ball.Coords, s.field.Center, s.rules.BallMaxSpeed)
+	}
+	return true
+}
+
+// findGoalCrossing checks the ball movement against both goals of the field, see arena.FindGoalCrossing
+func (s *Simulator) findGoalCrossing(from, to physics.Point) (arena.GoalCrossing, bool) {
+	for _, goal := range []arena.Goal{s.field.HomeGoal, s.field.AwayGoal} {
+		if crossing, ok := arena.CheckGoalCrossing(from, to, s.state.Ball.Size, goal); ok {
+			return crossing, true
+		}
+	}
+	return arena.GoalCrossing{}, false
+}
+
+func (s *Simulator) resetPositions() {
+	s.state.HomeTeam = s.resetTeam(s.state.HomeTeam, s.lineups[arena.HomeTeam])
+	s.state.AwayTeam = s.resetTeam(s.state.AwayTeam, s.lineups[arena.AwayTeam])
+	s.jumping = map[playerKey]int{}
+	s.resetBall()
+}
+
+func (s *Simulator) resetBall() {
+	s.holder = nil
+	s.inGoalZone = 0
+	s.state.Ball = arena.Ball{
+		Element: physics.Element{
+			Size:     s.rules.BallSize,
+			Coords:   s.field.Center,
+			Velocity: physics.NewZeroedVelocity(physics.East),
+		},
+	}
+}
+
+func (s *Simulator) resetTeam(team arena.Team, lineup Lineup) arena.Team {
+	reset := s.newTeam(team.Place, team.Name, lineup)
+	reset.Score = team.Score
+	return reset
+}
+
+// addVelocities sums the velocities without changing them. Velocity.Add cannot sum velocities with speed zero
+// because their direction cannot be scaled to the speed.
+func (s *Simulator) addVelocities(a, b physics.Velocity) physics.Velocity {
+	if b.Speed == 0 {
+		return a.Copy()
+	}
+	if a.Speed == 0 {
+		return b.Copy()
+	}
+	if s.fixed != nil {
+		return fixed.FromVelocity(a).Add(fixed.FromVelocity(b)).Physics()
+	}
+	sum := a.Copy()
+	sum.Add(b.Copy())
+	return sum
+}
+
+// limitSpeed returns a copy of the velocity with the speed limited to `max`. In the deterministic mode the velocity
+// is also converted to the fixed-point precision, so the float values sent by the players do not leak into the match.
+func (s *Simulator) limitSpeed(velocity physics.Velocity, max float64) physics.Velocity {
+	if velocity.Direction == nil {
+		return physics.NewZeroedVelocity(physics.East)
+	}
+	limited := velocity.Copy()
+	limited.Speed = math.Max(0, math.Min(limited.Speed, max))
+	if s.fixed != nil {
+		return fixed.FromVelocity(limited).Physics()
+	}
+	return limited
+}
+
+// touches tells if the elements bodies are touching each other
+func (s *Simulator) touches(a, b physics.Element) bool {
+	if s.fixed != nil {
+		return fixed.FromElement(a).HasCollided(fixed.FromElement(b))
+	}
+	touching, _ := a.HasCollided(&b)
+	return touching
+}
+
+// playerTarget returns the point reached by the player moving with its velocity, limited to the playable area
+func (s *Simulator) playerTarget(player physics.Element) physics.Point {
+	if s.fixed != nil {
+		return s.fixed.ClampPlayerTarget(player.Coords, fixed.FromVelocity(player.Velocity))
+	}
+	return s.engine.ClampPlayerTarget(player.Coords, player.Velocity)
+}
@@ -0,0 +1,266 @@
+package sim
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/physics/fixed"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func batch(place arena.TeamPlace, number arena.PlayerNumber, list ...orders.Order) orders.Batch {
+	return orders.Batch{Place: place, Number: number, Orders: list}
+}
+
+func playerOf(s *Simulator, place arena.TeamPlace, number arena.PlayerNumber) *arena.Player {
+	snapshot := s.Snapshot()
+	return snapshot.Player(place, number)
+}
+
+func TestNew(t *testing.T) {
+	s := New(Config{HomeTeamName: "a", AwayTeamName: "b"})
+	snapshot := s.Snapshot()
+	assert.Len(t, snapshot.Players(), 22)
+	assert.Equal(t, arena.FieldCenter, snapshot.Ball.Coords)
+	assert.Nil(t, snapshot.Ball.Holder)
+	assert.Equal(t, arena.Listening, snapshot.State)
+	assert.Equal(t, "b", snapshot.AwayTeam.Name)
+	assert.Equal(t, arena.Mirror(arena.AwayTeam).Point(DefaultLineup["5"]), snapshot.Player(arena.AwayTeam, "5").Coords)
+}
+
+func TestSimulator_Move(t *testing.T) {
+	s := New(Config{})
+	player := playerOf(s, arena.HomeTeam, "7")
+	target := physics.Point{PosX: player.Coords.PosX + 1000, PosY: player.Coords.PosY}
+
+	s.Step([]orders.Batch{batch(arena.HomeTeam, "7", orders.NewMoveOrder(physics.NewVelocityTo(player.Coords, target, 500)))})
+	moved := playerOf(s, arena.HomeTeam, "7")
+	assert.Equal(t, physics.Point{PosX: player.Coords.PosX + units.PlayerMaxSpeed, PosY: player.Coords.PosY}, moved.Coords)
+	assert.Equal(t, units.PlayerMaxSpeed, moved.Velocity.Speed)
+
+	// the player keeps its velocity
+	s.Step(nil)
+	assert.Equal(t, player.Coords.PosX+2*units.PlayerMaxSpeed, playerOf(s, arena.HomeTeam, "7").Coords.PosX)
+	assert.Equal(t, 2, s.Turn())
+}
+
+func TestSimulator_CatchAndScore(t *testing.T) {
+	lineup := Lineup{"9": {PosX: units.FieldWidth/2 - 200, PosY: units.FieldHeight / 2}}
+	s := New(Config{HomeLineup: lineup, AwayLineup: Lineup{"1": DefaultLineup["1"]}})
+
+	// a player that does not touch the ball cannot catch it
+	s.Step([]orders.Batch{batch(arena.AwayTeam, "1", orders.NewCatchOrder())})
+	assert.Nil(t, s.Snapshot().Ball.Holder)
+
+	s.Step([]orders.Batch{batch(arena.HomeTeam, "9", orders.NewCatchOrder())})
+	snapshot := s.Snapshot()
+	if assert.NotNil(t, snapshot.Ball.Holder) {
+		assert.Equal(t, arena.PlayerNumber("9"), snapshot.Ball.Holder.Number)
+	}
+	assert.Equal(t, snapshot.Player(arena.HomeTeam, "9").Coords, snapshot.Ball.Coords)
+
+	// dribbles towards the goal before kicking
+	run := orders.NewMoveOrder(physics.NewVelocityTo(snapshot.Ball.Coords, arena.AwayTeamGoal.Center, units.PlayerMaxSpeed))
+	for i := 0; i < 40; i++ {
+		s.Step([]orders.Batch{batch(arena.HomeTeam, "9", run)})
+	}
+	snapshot = s.Snapshot()
+	assert.Equal(t, snapshot.Player(arena.HomeTeam, "9").Coords, snapshot.Ball.Coords)
+
+	kick := physics.NewVelocityTo(snapshot.Ball.Coords, arena.AwayTeamGoal.Center, units.BallMaxSpeed)
+	s.Step([]orders.Batch{batch(arena.HomeTeam, "9", orders.NewKickOrder(kick))})
+	assert.Nil(t, s.Snapshot().Ball.Holder)
+
+	var result Result
+	for i := 0; i < 100 && result.Goal == nil; i++ {
+		result = s.Step(nil)
+	}
+	if assert.NotNil(t, result.Goal) {
+		assert.Equal(t, arena.HomeTeam, result.ScoredBy)
+		assert.Equal(t, arena.AwayTeam, result.Goal.Goal.Place)
+	}
+	snapshot = s.Snapshot()
+	assert.Equal(t, 1, snapshot.HomeTeam.Score)
+	assert.Equal(t, 0, snapshot.AwayTeam.Score)
+	assert.Equal(t, arena.Results, snapshot.State)
+	assert.Equal(t, arena.FieldCenter, snapshot.Ball.Coords)
+	assert.Equal(t, lineup["9"], snapshot.Player(arena.HomeTeam, "9").Coords)
+}
+
+func TestSimulator_Jump(t *testing.T) {
+	s := New(Config{})
+	keeper := playerOf(s, arena.AwayTeam, arena.GoalkeeperNumber)
+	up := physics.Point{PosX: keeper.Coords.PosX, PosY: keeper.Coords.PosY + 1000}
+
+	// only goalkeepers may jump
+	s.Step([]orders.Batch{batch(arena.AwayTeam, "2", orders.NewJumpOrder(physics.NewVelocityTo(keeper.Coords, up, 1000)))})
+	assert.Equal(t, 0.0, playerOf(s, arena.AwayTeam, "2").Velocity.Speed)
+
+	s.Step([]orders.Batch{batch(arena.AwayTeam, arena.GoalkeeperNumber, orders.NewJumpOrder(physics.NewVelocityTo(keeper.Coords, up, 1000)))})
+	assert.Equal(t, keeper.Coords.PosY+units.GoalKeeperJumpSpeed, playerOf(s, arena.AwayTeam, arena.GoalkeeperNumber).Coords.PosY)
+
+	// the jump cannot be interrupted
+	down := physics.Point{PosX: keeper.Coords.PosX, PosY: 0}
+	s.Step([]orders.Batch{batch(arena.AwayTeam, arena.GoalkeeperNumber, orders.NewMoveOrder(physics.NewVelocityTo(keeper.Coords, down, 50)))})
+	s.Step(nil)
+	jumped := playerOf(s, arena.AwayTeam, arena.GoalkeeperNumber)
+	assert.Equal(t, keeper.Coords.PosY+units.GoalKeeperJumpDuration*units.GoalKeeperJumpSpeed, jumped.Coords.PosY)
+	assert.Equal(t, 0.0, jumped.Velocity.Speed)
+
+	s.Step(nil)
+	assert.Equal(t, jumped.Coords, playerOf(s, arena.AwayTeam, arena.GoalkeeperNumber).Coords)
+}
+
+func TestSimulator_GoalZoneAutoKick(t *testing.T) {
+	s := New(Config{HomeLineup: Lineup{"1": {PosX: 800, PosY: units.FieldHeight / 2}}, AwayLineup: Lineup{}})
+	snapshot := s.Snapshot()
+	snapshot.Ball.Coords = physics.Point{PosX: 800, PosY: units.FieldHeight / 2}
+	s = NewFromSnapshot(snapshot, 1, 0)
+
+	s.Step([]orders.Batch{batch(arena.HomeTeam, arena.GoalkeeperNumber, orders.NewCatchOrder())})
+	var result Result
+	turns := 1
+	for ; turns < 100 && !result.AutoKicked; turns++ {
+		result = s.Step(nil)
+	}
+	assert.True(t, result.AutoKicked)
+	// the ball was in the goal zone since the first turn
+	assert.Equal(t, units.BallTimeInGoalZone+1, turns)
+	snapshot = s.Snapshot()
+	assert.Nil(t, snapshot.Ball.Holder)
+	assert.Equal(t, units.BallMaxSpeed, snapshot.Ball.Velocity.Speed)
+	assert.True(t, snapshot.Ball.Velocity.Direction.GetX() > 0)
+}
+
+func TestSimulator_OpponentGoalZone(t *testing.T) {
+	s := New(Config{HomeLineup: Lineup{"10": {PosX: units.FieldWidth - units.GoalZoneRange - 50, PosY: units.FieldHeight / 2}}})
+	player := playerOf(s, arena.HomeTeam, "10")
+	s.Step([]orders.Batch{batch(arena.HomeTeam, "10", orders.NewMoveOrder(physics.NewVelocityTo(player.Coords, arena.AwayTeamGoal.Center, units.PlayerMaxSpeed)))})
+	assert.Equal(t, player.Coords, playerOf(s, arena.HomeTeam, "10").Coords)
+}
+
+func TestNewFromSnapshot_Holder(t *testing.T) {
+	snapshot := New(Config{}).Snapshot()
+	holder := snapshot.Player(arena.HomeTeam, "9").Copy()
+	snapshot.Ball.Coords = holder.Coords
+	snapshot.Ball.Holder = &holder
+	s := NewFromSnapshot(snapshot, 1, 0)
+	assert.NotNil(t, s.Snapshot().Ball.Holder)
+
+	// the kicked ball is free
+	s.Step([]orders.Batch{batch(arena.HomeTeam, "9", orders.NewKickOrder(physics.NewVelocityTo(holder.Coords, arena.AwayTeamGoal.Center, 100)))})
+	assert.Nil(t, s.Snapshot().Ball.Holder)
+}
+
+func TestSimulator_Over(t *testing.T) {
+	s := New(Config{MaxTurns: 3})
+	s.Step(nil)
+	s.Step(nil)
+	assert.False(t, s.IsOver())
+	assert.True(t, s.Step(nil).Over)
+	assert.True(t, s.IsOver())
+	assert.Equal(t, arena.Over, s.Snapshot().State)
+	assert.Equal(t, 3, s.Step(nil).Turn)
+}
+
+func randomBatches(r *rand.Rand, snapshot arena.Snapshot) []orders.Batch {
+	var batches []orders.Batch
+	for _, player := range snapshot.Players() {
+		target := physics.Point{PosX: r.Intn(units.FieldWidth), PosY: r.Intn(units.FieldHeight)}
+		if target == player.Coords {
+			continue
+		}
+		list := []orders.Order{orders.NewMoveOrder(physics.NewVelocityTo(player.Coords, target, units.PlayerMaxSpeed)), orders.NewCatchOrder()}
+		if r.Intn(4) == 0 {
+			list = append(list, orders.NewKickOrder(physics.NewVelocityTo(player.Coords, target, units.BallMaxSpeed)))
+		}
+		batches = append(batches, batch(player.TeamPlace, player.Number, list...))
+	}
+	return batches
+}
+
+func TestSimulator_Deterministic(t *testing.T) {
+	play := func() []arena.Snapshot {
+		s := New(Config{Seed: 99, MaxTurns: 300})
+		r := rand.New(rand.NewSource(5))
+		var history []arena.Snapshot
+		for !s.IsOver() {
+			s.Step(randomBatches(r, s.Snapshot()))
+			history = append(history, s.Snapshot())
+		}
+		return history
+	}
+	first := play()
+	assert.Len(t, first, 300)
+	assert.Equal(t, first, play())
+}
+
+func BenchmarkSimulator_Match(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s := New(Config{Seed: int64(i)})
+		r := rand.New(rand.NewSource(int64(i)))
+		for !s.IsOver() {
+			s.Step(randomBatches(r, s.Snapshot()))
+		}
+	}
+}
+
+func TestSimulator_Rules(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.FieldWidth = 30000
+	rules.PlayerMaxSpeed = 200
+	s := New(Config{Rules: rules})
+	snapshot := s.Snapshot()
+	assert.Equal(t, physics.Point{PosX: 15000, PosY: rules.FieldHeight / 2}, snapshot.Ball.Coords)
+	away := snapshot.Player(arena.AwayTeam, "5")
+	assert.Equal(t, rules.FieldWidth-DefaultLineup["5"].PosX, away.Coords.PosX)
+
+	s.Step([]orders.Batch{batch(arena.AwayTeam, "5", orders.NewMoveOrder(physics.NewVelocityTo(away.Coords, physics.Point{PosY: away.Coords.PosY}, 500)))})
+	assert.Equal(t, 200.0, playerOf(s, arena.AwayTeam, "5").Velocity.Speed)
+
+	// the snapshot rules are kept by the simulator created from it
+	s = NewFromSnapshotWithRules(s.Snapshot(), rules, 1, 0)
+	s.Step(nil)
+	assert.Equal(t, away.Coords.PosX-400, playerOf(s, arena.AwayTeam, "5").Coords.PosX)
+}
+
+func TestSimulator_BallStops(t *testing.T) {
+	snapshot := New(Config{}).Snapshot()
+	speed := float64(units.BallMinSpeed + units.BallDeceleration)
+	snapshot.Ball.Velocity = physics.NewVelocityTo(physics.Point{}, physics.Point{PosY: 1}, speed)
+	s := NewFromSnapshot(snapshot, 1, 0)
+
+	// the ball stops as soon as its speed reaches BallMinSpeed, as predicted by physics.BallTrajectory
+	s.Step(nil)
+	ball := s.Snapshot().Ball
+	assert.Equal(t, 0.0, ball.Velocity.Speed)
+	path := physics.BallTrajectory(snapshot.Ball.Coords, snapshot.Ball.Velocity, 0)
+	assert.Equal(t, path[len(path)-1], ball.Coords)
+}
+
+func TestSimulator_FixedPhysics(t *testing.T) {
+	rules := units.DefaultRules()
+	rules.PlayerMaxSpeed = 200
+	s := New(Config{Rules: rules, Deterministic: true})
+	assert.True(t, s.IsDeterministic())
+	assert.False(t, New(Config{}).IsDeterministic())
+
+	player := playerOf(s, arena.HomeTeam, "7")
+	target := physics.Point{PosX: player.Coords.PosX + 3000, PosY: player.Coords.PosY + 1000}
+	s.Step([]orders.Batch{batch(arena.HomeTeam, "7", orders.NewMoveOrder(physics.NewVelocityTo(player.Coords, target, 500)))})
+	moved := playerOf(s, arena.HomeTeam, "7")
+	// the velocity sent by the player is converted to the fixed-point precision, and the speed follows the rules
+	assert.Equal(t, fixed.FromVelocity(moved.Velocity).Physics(), moved.Velocity)
+	assert.Equal(t, 200.0, moved.Velocity.Speed)
+	expected := fixed.NewVelocityTo(player.Coords, target, fixed.FromInt(200)).Target(player.Coords)
+	assert.Equal(t, expected, moved.Coords)
+
+	// the match is the same when continued from a snapshot
+	continued := NewFromSnapshotWithConfig(s.Snapshot(), Config{Rules: rules, Deterministic: true})
+	s.Step(nil)
+	continued.Step(nil)
+	assert.Equal(t, s.Snapshot(), continued.Snapshot())
+}
@@ -0,0 +1,118 @@
+package arena
+
+import (
+	"github.com/lugobots/arena/physics"
+)
+
+// Player is a player element in the field
+type Player struct {
+	physics.Element
+	// Number identifies the number of the player in its team
+	Number PlayerNumber `json:"number"`
+	// TeamPlace identifies the team of the player
+	TeamPlace TeamPlace `json:"team_place"`
+}
+
+// Ball is the ball element in the field
+type Ball struct {
+	physics.Element
+	// Holder is the player holding the ball. It is nil when the ball is free
+	Holder *Player `json:"holder"`
+}
+
+// Team is the set of values about a team during the game
+type Team struct {
+	// Place identifies the side of the team
+	Place TeamPlace `json:"place"`
+	// Name is the team name
+	Name string `json:"name"`
+	// Score is the number of goals scored by the team
+	Score int `json:"score"`
+	// Players are the players of the team
+	Players []Player `json:"players"`
+}
+
+// Snapshot is the state of all game elements in a turn
+type Snapshot struct {
+	// Turn is the turn number of the snapshot
+	Turn int `json:"turn"`
+	// State is the game state when the snapshot was taken
+	State GameState `json:"state"`
+	// Ball is the ball element
+	Ball Ball `json:"ball"`
+	// HomeTeam is the team playing on the left side
+	HomeTeam Team `json:"home_team"`
+	// AwayTeam is the team playing on the right side
+	AwayTeam Team `json:"away_team"`
+}
+
+// Team returns the team that plays on the side `place`
+func (s *Snapshot) Team(place TeamPlace) *Team {
+	if place == AwayTeam {
+		return &s.AwayTeam
+	}
+	return &s.HomeTeam
+}
+
+// Player finds a player in the snapshot. It returns nil when the player is not found
+func (s *Snapshot) Player(place TeamPlace, number PlayerNumber) *Player {
+	team := s.Team(place)
+	for i := range team.Players {
+		if team.Players[i].Number == number {
+			return &team.Players[i]
+		}
+	}
+	return nil
+}
+
+// Players returns all players of both teams, home team players first
+func (s *Snapshot) Players() []Player {
+	players := make([]Player, 0, len(s.HomeTeam.Players)+len(s.AwayTeam.Players))
+	players = append(players, s.HomeTeam.Players...)
+	return append(players, s.AwayTeam.Players...)
+}
+
+// Copy creates a deep copy of the snapshot, so the copy may be changed without affecting the original one
+func (s *Snapshot) Copy() Snapshot {
+	copied := *s
+	copied.Ball = s.Ball.Copy()
+	copied.HomeTeam = s.HomeTeam.Copy()
+	copied.AwayTeam = s.AwayTeam.Copy()
+	return copied
+}
+
+// Copy creates a deep copy of the team
+func (t *Team) Copy() Team {
+	copied := *t
+	copied.Players = make([]Player, len(t.Players))
+	for i, player := range t.Players {
+		copied.Players[i] = player.Copy()
+	}
+	return copied
+}
+
+// Copy creates a deep copy of the player
+func (p *Player) Copy() Player {
+	copied := *p
+	copied.Element = CopyElement(p.Element)
+	return copied
+}
+
+// Copy creates a deep copy of the ball
+func (b *Ball) Copy() Ball {
+	copied := *b
+	copied.Element = CopyElement(b.Element)
+	if b.Holder != nil {
+		holder := b.Holder.Copy()
+		copied.Holder = &holder
+	}
+	return copied
+}
+
+// CopyElement creates a copy of an element that does not share the velocity direction with the original one
+func CopyElement(e physics.Element) physics.Element {
+	if e.Velocity.Direction != nil {
+		e.Velocity = e.Velocity.Copy()
+	}
+	return e
+}
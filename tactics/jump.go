@@ -0,0 +1,166 @@
+package tactics
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"math"
+)
+
+// SaveMargin is the error expected in the predictions, in field units. A goalkeeper that reaches the ball exactly at
+// the touch distance has 50% of chance of saving it, and the chance grows (or decreases) linearly until the
+// goalkeeper is SaveMargin units closer (or farther) than that.
+const SaveMargin = float64(units.BallSize)
+
+// JumpPlan is the decision of the goalkeeper about a ball moving towards its goal
+type JumpPlan struct {
+	// Threat is true when the ball is predicted to enter the goal
+	Threat bool
+	// Crossing is where the ball crosses the goal line. It is only set when the ball is a threat
+	Crossing arena.GoalCrossing
+	// CrossingTurn is the turn when the ball crosses the goal line (1 is the next turn)
+	CrossingTurn int
+	// Jump is true when jumping is the best way to save the ball. When it is false, the goalkeeper should run towards
+	// the ball (see RunVelocity) and try to catch it
+	Jump bool
+	// StartTurn is how many turns the goalkeeper should wait before jumping. Zero means the jump order should be sent
+	// now
+	StartTurn int
+	// Velocity is the jump velocity (see orders.JumpOrderData). It is only set when Jump is true
+	Velocity physics.Velocity
+	// RunVelocity is the velocity to run towards the ball when the goalkeeper does not need to jump
+	RunVelocity physics.Velocity
+	// SaveTurn is the turn when the goalkeeper is predicted to touch the ball
+	SaveTurn int
+	// SaveProbability is the predicted chance of saving the ball (0 to 1). It is 1 when the ball is not a threat
+	SaveProbability float64
+}
+
+// Order creates the jump order of the plan
+func (j JumpPlan) Order() orders.Order {
+	return orders.NewJumpOrder(j.Velocity)
+}
+
+// ShouldJumpNow tells if the jump order should be sent in the current turn
+func (j JumpPlan) ShouldJumpNow() bool {
+	return j.Threat && j.Jump && j.StartTurn == 0
+}
+
+// PlanJump decides if and when the goalkeeper should jump to save the ball, see Planner.PlanJump
+func PlanJump(goalkeeper physics.Element, ball physics.Point, trajectory []physics.Point, goal arena.Goal) JumpPlan {
+	return defaultPlanner.PlanJump(goalkeeper, ball, trajectory, goal)
+}
+
+// PlanJumpForBall predicts the ball trajectory and decides if and when the goalkeeper should jump to save it
+func PlanJumpForBall(goalkeeper physics.Element, ball physics.Element, goal arena.Goal) JumpPlan {
+	return defaultPlanner.PlanJumpForBall(goalkeeper, ball, goal)
+}
+
+// PlanJumpForBall predicts the ball trajectory and decides if and when the goalkeeper should jump to save it
+func (p Planner) PlanJumpForBall(goalkeeper physics.Element, ball physics.Element, goal arena.Goal) JumpPlan {
+	return p.PlanJump(goalkeeper, ball.Coords, p.engine.BallTrajectory(ball.Coords, ball.Velocity, 0), goal)
+}
+
+// PlanJump decides if and when the goalkeeper should jump to save the ball that leaves `ball` following the
+// trajectory (see physics.BallTrajectory). The goalkeeper must touch the ball before it crosses the goal line.
+// Running towards the ball is preferred when it gives the same chance of saving it, since a jump cannot be
+// interrupted. Among the jumps with the best chance, the latest one is chosen, so the goalkeeper waits as long as
+// possible for a better prediction. Until the jump, the goalkeeper is expected to keep its current velocity.
+// A goalkeeper that is already jumping (see arena.MatchClock) cannot follow the plan.
+func (p Planner) PlanJump(goalkeeper physics.Element, ball physics.Point, trajectory []physics.Point, goal arena.Goal) JumpPlan {
+	crossing, crossingTurn, ok := p.findCrossing(ball, trajectory, goal)
+	if !ok || !crossing.Scored {
+		return JumpPlan{SaveProbability: 1}
+	}
+	plan := JumpPlan{Threat: true, Crossing: crossing, CrossingTurn: crossingTurn}
+	// the ball must be touched before the turn it crosses the line
+	reachable := trajectory[:crossingTurn-1]
+
+	// the goalkeeper may already be touching the ball
+	runProbability, runTurn := p.saveProbability(p.touchDistance()-goalkeeper.Coords.DistanceTo(ball)), 0
+	for turn := 1; turn <= len(reachable); turn++ {
+		velocity, ok := velocityTowards(goalkeeper.Coords, reachable[turn-1], turn, p.Rules.PlayerMaxSpeed)
+		if !ok {
+			velocity = physics.NewZeroedVelocity(physics.East)
+		}
+		path := p.playerPath(goalkeeper.Coords, velocity, len(reachable))
+		saveTurn, slack := p.closestApproach(path, reachable)
+		if probability := p.saveProbability(slack); probability > runProbability {
+			runProbability, runTurn = probability, saveTurn
+			plan.RunVelocity = velocity
+		}
+	}
+
+	jumpProbability, jumpStart, jumpTurn := 0.0, 0, 0
+	var jumpVelocity physics.Velocity
+	waiting := p.playerPath(goalkeeper.Coords, goalkeeper.Velocity, len(reachable))
+	for start := 0; start < len(reachable); start++ {
+		from := goalkeeper.Coords
+		if start > 0 {
+			from = waiting[start-1]
+		}
+		for turn := start + 1; turn <= len(reachable); turn++ {
+			duration := int(math.Min(float64(turn-start), float64(p.Rules.GoalKeeperJumpDuration)))
+			velocity, ok := velocityTowards(from, reachable[turn-1], duration, p.Rules.GoalKeeperJumpSpeed)
+			if !ok {
+				continue
+			}
+			path := append(append([]physics.Point{}, waiting[:start]...), p.jumpPath(from, velocity, len(reachable)-start)...)
+			saveTurn, slack := p.closestApproach(path, reachable)
+			// later starts win the ties
+			if probability := p.saveProbability(slack); probability >= jumpProbability && probability > 0 {
+				jumpProbability, jumpStart, jumpTurn, jumpVelocity = probability, start, saveTurn, velocity
+			}
+		}
+	}
+
+	if jumpProbability > runProbability {
+		plan.Jump = true
+		plan.StartTurn = jumpStart
+		plan.Velocity = jumpVelocity
+		plan.SaveTurn = jumpTurn
+		plan.SaveProbability = jumpProbability
+		return plan
+	}
+	plan.SaveTurn = runTurn
+	plan.SaveProbability = runProbability
+	return plan
+}
+
+// jumpPath predicts the goalkeeper positions during and after a jump with the velocity
+func (p Planner) jumpPath(from physics.Point, velocity physics.Velocity, turns int) []physics.Point {
+	jumping := int(math.Min(float64(turns), float64(p.Rules.GoalKeeperJumpDuration)))
+	path := p.playerPath(from, velocity, jumping)
+	last := from
+	if len(path) > 0 {
+		last = path[len(path)-1]
+	}
+	// the goalkeeper stops at the end of the jump
+	for len(path) < turns {
+		path = append(path, last)
+	}
+	return path
+}
+
+// closestApproach finds the turn when the player gets closer to the ball, comparing the positions of the same turns.
+// The slack is how much closer than the touch distance the player gets (negative when it does not touch the ball).
+func (p Planner) closestApproach(player, ball []physics.Point) (int, float64) {
+	bestTurn, bestSlack := 0, math.Inf(-1)
+	for i := 0; i < len(player) && i < len(ball); i++ {
+		slack := p.touchDistance() - player[i].DistanceTo(ball[i])
+		if slack > bestSlack {
+			bestTurn, bestSlack = i+1, slack
+		}
+		if slack >= SaveMargin {
+			// touching the ball as soon as possible is better than getting closer later
+			break
+		}
+	}
+	return bestTurn, bestSlack
+}
+
+// saveProbability converts the slack of the closest approach to the chance of saving the ball
+func (p Planner) saveProbability(slack float64) float64 {
+	return clamp(0.5+slack/(2*SaveMargin), 0, 1)
+}
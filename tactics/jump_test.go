@@ -0,0 +1,112 @@
+package tactics
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/sim"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// newTestShot creates a match with the home goalkeeper in front of its goal and the ball kicked from `from` to `to`
+func newTestShot(from, to physics.Point, speed float64) *sim.Simulator {
+	s := sim.New(sim.Config{HomeLineup: sim.Lineup{"1": {PosX: units.PlayerSize, PosY: units.FieldHeight / 2}}, AwayLineup: sim.Lineup{}})
+	snapshot := s.Snapshot()
+	snapshot.Ball.Coords = from
+	snapshot.Ball.Velocity = physics.NewVelocityTo(from, to, speed)
+	return sim.NewFromSnapshot(snapshot, 1, 0)
+}
+
+// followPlan plays the shot with the goalkeeper following the plan found in each turn. It returns true if the ball
+// was saved.
+func followPlan(s *sim.Simulator) bool {
+	jumped := false
+	for turn := 0; turn < 30; turn++ {
+		snapshot := s.Snapshot()
+		if snapshot.Ball.Holder != nil {
+			return true
+		}
+		keeper := snapshot.Player(arena.HomeTeam, arena.GoalkeeperNumber)
+		plan := PlanJumpForBall(keeper.Element, snapshot.Ball.Element, arena.HomeTeamGoal)
+		list := []orders.Order{orders.NewCatchOrder()}
+		switch {
+		case plan.ShouldJumpNow() && !jumped:
+			list = append(list, plan.Order())
+			jumped = true
+		case plan.Threat && !plan.Jump && plan.RunVelocity.Direction != nil:
+			list = append(list, orders.NewMoveOrder(plan.RunVelocity))
+		}
+		if result := s.Step([]orders.Batch{{Place: arena.HomeTeam, Number: arena.GoalkeeperNumber, Orders: list}}); result.Goal != nil {
+			return false
+		}
+	}
+	return s.Snapshot().Ball.Holder != nil
+}
+
+func TestPlanJump_NoThreat(t *testing.T) {
+	keeper := physics.Element{Size: units.PlayerSize, Coords: physics.Point{PosX: units.PlayerSize, PosY: units.FieldHeight / 2}}
+	ball := physics.Point{PosX: 3000, PosY: units.FieldHeight / 2}
+
+	// a shot going wide
+	wide := physics.NewVelocityTo(ball, physics.Point{PosX: 0, PosY: units.GoalMaxY + 1500}, units.BallMaxSpeed)
+	plan := PlanJump(keeper, ball, physics.BallTrajectory(ball, wide, 0), arena.HomeTeamGoal)
+	assert.False(t, plan.Threat)
+	assert.False(t, plan.ShouldJumpNow())
+	assert.Equal(t, 1.0, plan.SaveProbability)
+
+	// a slow ball that stops before the goal line
+	slow := physics.NewVelocityTo(ball, arena.HomeTeamGoal.Center, 50)
+	plan = PlanJump(keeper, ball, physics.BallTrajectory(ball, slow, 0), arena.HomeTeamGoal)
+	assert.False(t, plan.Threat)
+
+	// a shot to the other goal
+	plan = PlanJump(keeper, ball, physics.BallTrajectory(ball, physics.NewVelocityTo(ball, arena.HomeTeamGoal.Center, 300), 0), arena.AwayTeamGoal)
+	assert.False(t, plan.Threat)
+}
+
+func TestPlanJump_ShotAtTheGoalkeeper(t *testing.T) {
+	s := newTestShot(physics.Point{PosX: 3000, PosY: units.FieldHeight / 2}, arena.HomeTeamGoal.Center, 300)
+	snapshot := s.Snapshot()
+	plan := PlanJumpForBall(snapshot.Player(arena.HomeTeam, arena.GoalkeeperNumber).Element, snapshot.Ball.Element, arena.HomeTeamGoal)
+	assert.True(t, plan.Threat)
+	assert.Equal(t, arena.HomeTeamGoal.Center, plan.Crossing.Point)
+	assert.False(t, plan.Jump)
+	assert.Equal(t, 1.0, plan.SaveProbability)
+	assert.True(t, followPlan(s))
+}
+
+func TestPlanJump_ShotToTheCorner(t *testing.T) {
+	s := newTestShot(physics.Point{PosX: 1800, PosY: units.FieldHeight / 2}, physics.Point{PosX: 0, PosY: units.GoalMaxY - 200}, units.BallMaxSpeed)
+	snapshot := s.Snapshot()
+	plan := PlanJumpForBall(snapshot.Player(arena.HomeTeam, arena.GoalkeeperNumber).Element, snapshot.Ball.Element, arena.HomeTeamGoal)
+	assert.True(t, plan.Threat)
+	assert.True(t, plan.Jump)
+	assert.True(t, plan.ShouldJumpNow())
+	assert.Equal(t, units.GoalKeeperJumpSpeed, plan.Velocity.Speed)
+	assert.True(t, plan.Velocity.Direction.GetY() > 0)
+	assert.True(t, plan.SaveTurn < plan.CrossingTurn)
+	assert.True(t, plan.SaveProbability > 0.5)
+	assert.Equal(t, orders.JUMP, plan.Order().Type)
+	assert.True(t, followPlan(s))
+}
+
+func TestPlanJump_LateJump(t *testing.T) {
+	s := newTestShot(physics.Point{PosX: 2500, PosY: units.FieldHeight / 2}, physics.Point{PosX: 0, PosY: units.GoalMaxY - 200}, units.BallMaxSpeed)
+	snapshot := s.Snapshot()
+	plan := PlanJumpForBall(snapshot.Player(arena.HomeTeam, arena.GoalkeeperNumber).Element, snapshot.Ball.Element, arena.HomeTeamGoal)
+	assert.True(t, plan.Jump)
+	// jumping now would leave the goalkeeper stopped before the ball arrives
+	assert.True(t, plan.StartTurn > 0)
+	assert.False(t, plan.ShouldJumpNow())
+}
+
+func TestPlanJump_Unreachable(t *testing.T) {
+	s := newTestShot(physics.Point{PosX: 1000, PosY: units.GoalMinY + 400}, physics.Point{PosX: 0, PosY: units.GoalMinY + 100}, units.BallMaxSpeed)
+	snapshot := s.Snapshot()
+	plan := PlanJumpForBall(snapshot.Player(arena.HomeTeam, arena.GoalkeeperNumber).Element, snapshot.Ball.Element, arena.HomeTeamGoal)
+	assert.True(t, plan.Threat)
+	assert.Equal(t, 0.0, plan.SaveProbability)
+	assert.False(t, followPlan(s))
+}
@@ -0,0 +1,73 @@
+// Package tactics turns the physics helpers into ready-made decisions for the bots (e.g. when the goalkeeper should
+// jump, or where to shoot). All predictions follow the game rules, so they are as good as the ball and players
+// positions they are fed with.
+package tactics
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/units"
+	"math"
+)
+
+// Planner makes the tactical decisions using a rule set. The package functions use the default rules.
+type Planner struct {
+	Rules  units.Rules
+	engine physics.Engine
+}
+
+// NewPlanner creates a planner that follows the rules
+func NewPlanner(rules units.Rules) Planner {
+	return Planner{Rules: rules, engine: physics.NewEngine(rules)}
+}
+
+// defaultPlanner follows the rules defined by the units constants
+var defaultPlanner = NewPlanner(units.DefaultRules())
+
+// touchDistance is the max distance between the centers of a player and the ball when they touch each other
+func (p Planner) touchDistance() float64 {
+	return float64(p.Rules.PlayerSize+p.Rules.BallSize) / 2
+}
+
+// playerPath predicts the positions of a player moving with the velocity during the turns, keeping its body inside
+// the field as the server does
+func (p Planner) playerPath(from physics.Point, velocity physics.Velocity, turns int) []physics.Point {
+	path := make([]physics.Point, 0, turns)
+	position := from
+	for i := 0; i < turns; i++ {
+		if velocity.Direction != nil && velocity.Speed > 0 {
+			position = p.engine.ClampPlayerTarget(position, velocity)
+		}
+		path = append(path, position)
+	}
+	return path
+}
+
+// findCrossing finds the turn when the ball following the trajectory crosses the goal line. The second value is
+// false when the ball does not reach the goal line.
+func (p Planner) findCrossing(from physics.Point, trajectory []physics.Point, goal arena.Goal) (arena.GoalCrossing, int, bool) {
+	previous := from
+	for i, position := range trajectory {
+		if crossing, ok := arena.CheckGoalCrossing(previous, position, p.Rules.BallSize, goal); ok {
+			return crossing, i + 1, true
+		}
+		previous = position
+	}
+	return arena.GoalCrossing{}, 0, false
+}
+
+// velocityTowards creates the velocity to go from `from` to `to` in `turns` turns, limited to `maxSpeed`. The second
+// value is false when the points are the same.
+func velocityTowards(from, to physics.Point, turns int, maxSpeed float64) (physics.Velocity, bool) {
+	direction, err := physics.NewVector(from, to)
+	if err != nil {
+		return physics.Velocity{}, false
+	}
+	velocity := physics.NewZeroedVelocity(*direction.Normalize())
+	velocity.Speed = math.Min(maxSpeed, from.DistanceTo(to)/float64(turns))
+	return velocity, true
+}
+
+func clamp(value, min, max float64) float64 {
+	return math.Max(min, math.Min(value, max))
+}
@@ -0,0 +1,140 @@
+package tactics
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"math"
+)
+
+// ShotDirections is the number of directions sampled between the goal poles
+const ShotDirections = 15
+
+// ShotSpeeds is the number of kick speeds sampled for each direction, evenly spaced up to the ball max speed
+const ShotSpeeds = 4
+
+// ShotEvaluation is the evaluation of a kick towards the goal
+type ShotEvaluation struct {
+	// Target is the point of the goal line where the ball was aimed
+	Target physics.Point
+	// Velocity is the velocity of the kick order. It compensates the holder velocity, that is added to the kick
+	Velocity physics.Velocity
+	// BallVelocity is the velocity of the ball right after the kick
+	BallVelocity physics.Velocity
+	// Scores is false when the ball stops or leaves the goal mouth before crossing the goal line
+	Scores bool
+	// CrossingTurn is the turn when the ball crosses the goal line
+	CrossingTurn int
+	// SaveProbability is the chance of the goalkeeper saving the ball (see PlanJump)
+	SaveProbability float64
+	// InterceptionRisk is the chance of any other opponent touching the ball before it crosses the goal line
+	InterceptionRisk float64
+	// Interceptor is the first opponent able to reach the ball, nil when no opponent reaches it in time
+	Interceptor *arena.Player
+	// Chance is the chance of scoring (0 to 1)
+	Chance float64
+}
+
+// Order creates the kick order of the shot
+func (s ShotEvaluation) Order() orders.Order {
+	return orders.NewKickOrder(s.Velocity)
+}
+
+// BestShot finds the kick with the highest chance of scoring, see Planner.BestShot
+func BestShot(holder arena.Player, opponents []arena.Player, goal arena.Goal) (ShotEvaluation, bool) {
+	return defaultPlanner.BestShot(holder, opponents, goal)
+}
+
+// EvaluateShot evaluates a kick towards the target, see Planner.EvaluateShot
+func EvaluateShot(holder arena.Player, opponents []arena.Player, target physics.Point, speed float64, goal arena.Goal) ShotEvaluation {
+	return defaultPlanner.EvaluateShot(holder, opponents, target, speed, goal)
+}
+
+// BestShot samples ShotDirections directions between the goal poles and ShotSpeeds speeds up to the ball max speed,
+// and returns the kick with the highest chance of scoring. Between shots with the same chance, the one reaching the
+// goal first is preferred. The second value is false when no sampled kick reaches the goal.
+func (p Planner) BestShot(holder arena.Player, opponents []arena.Player, goal arena.Goal) (ShotEvaluation, bool) {
+	var best ShotEvaluation
+	found := false
+	radius := p.Rules.BallSize / 2
+	bottom, top := goal.BottomPole.PosY+radius, goal.TopPole.PosY-radius
+	for i := 0; i < ShotDirections; i++ {
+		target := physics.Point{PosX: goal.Center.PosX, PosY: (bottom + top) / 2}
+		if ShotDirections > 1 {
+			target.PosY = bottom + int(math.Round(float64(i*(top-bottom))/float64(ShotDirections-1)))
+		}
+		for j := 1; j <= ShotSpeeds; j++ {
+			shot := p.EvaluateShot(holder, opponents, target, p.Rules.BallMaxSpeed*float64(j)/ShotSpeeds, goal)
+			if !shot.Scores {
+				continue
+			}
+			if !found || shot.Chance > best.Chance || (shot.Chance == best.Chance && shot.CrossingTurn < best.CrossingTurn) {
+				best, found = shot, true
+			}
+		}
+	}
+	return best, found
+}
+
+// EvaluateShot evaluates a kick of the holder that sends the ball towards the target with the speed (limited to the
+// ball max speed). The ball decelerates as described by physics.BallTrajectory. The goalkeeper (the opponent with
+// the goalkeeper number) may run or jump to save the ball, and the other opponents run towards the ball path at the
+// player max speed (see physics.EvaluateInterception).
+func (p Planner) EvaluateShot(holder arena.Player, opponents []arena.Player, target physics.Point, speed float64, goal arena.Goal) ShotEvaluation {
+	shot := ShotEvaluation{Target: target}
+	direction, err := physics.NewVector(holder.Coords, target)
+	if err != nil {
+		return shot
+	}
+	ball := physics.NewZeroedVelocity(*direction.Normalize())
+	ball.Speed = math.Min(speed, p.Rules.BallMaxSpeed)
+	shot.BallVelocity = ball
+	shot.Velocity = kickVelocity(holder.Velocity, ball)
+
+	trajectory := p.engine.BallTrajectory(holder.Coords, ball, 0)
+	crossing, crossingTurn, ok := p.findCrossing(holder.Coords, trajectory, goal)
+	if !ok || !crossing.Scored {
+		return shot
+	}
+	shot.Scores = true
+	shot.CrossingTurn = crossingTurn
+	reachable := trajectory[:crossingTurn-1]
+
+	safe := 1.0
+	interceptionTurn := 0
+	for i := range opponents {
+		opponent := &opponents[i]
+		if opponent.Number == arena.GoalkeeperNumber {
+			plan := p.PlanJump(opponent.Element, holder.Coords, trajectory, goal)
+			shot.SaveProbability = math.Max(shot.SaveProbability, plan.SaveProbability)
+			continue
+		}
+		risk := p.engine.EvaluateInterception(&opponent.Element, holder.Coords, reachable)
+		safe *= 1 - risk.Risk
+		if risk.Intercepts && (shot.Interceptor == nil || risk.Turn < interceptionTurn) {
+			shot.Interceptor, interceptionTurn = opponent, risk.Turn
+		}
+	}
+	shot.InterceptionRisk = 1 - safe
+	shot.Chance = safe * (1 - shot.SaveProbability)
+	return shot
+}
+
+// kickVelocity finds the kick that gives the ball velocity to a ball held by a player moving with the holder
+// velocity, since the server adds the player velocity to the kick
+func kickVelocity(holder, ball physics.Velocity) physics.Velocity {
+	if holder.Direction == nil || holder.Speed == 0 {
+		return ball.Copy()
+	}
+	desired := ball.Direction.Copy()
+	desired.SetLength(ball.Speed)
+	moving := holder.Direction.Copy()
+	moving.SetLength(holder.Speed)
+	if _, err := desired.Sub(moving); err != nil || desired.Length() == 0 {
+		// the ball already goes with the holder velocity, so the kick only releases it
+		return physics.NewZeroedVelocity(*ball.Direction.Copy())
+	}
+	kick := physics.NewZeroedVelocity(*desired.Copy().Normalize())
+	kick.Speed = desired.Length()
+	return kick
+}
@@ -0,0 +1,137 @@
+package tactics
+
+import (
+	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/physics"
+	"github.com/lugobots/arena/sim"
+	"github.com/lugobots/arena/units"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+// newTestAttack creates a match where the home player 9 holds the ball at `position`. The away lineup is in the away
+// team frame.
+func newTestAttack(position physics.Point, away sim.Lineup) *sim.Simulator {
+	s := sim.New(sim.Config{HomeLineup: sim.Lineup{"9": position}, AwayLineup: away})
+	snapshot := s.Snapshot()
+	holder := snapshot.Player(arena.HomeTeam, "9").Copy()
+	snapshot.Ball.Coords = holder.Coords
+	snapshot.Ball.Holder = &holder
+	return sim.NewFromSnapshot(snapshot, 1, 0)
+}
+
+func attackers(s *sim.Simulator) (arena.Player, []arena.Player) {
+	snapshot := s.Snapshot()
+	return *snapshot.Player(arena.HomeTeam, "9"), snapshot.AwayTeam.Players
+}
+
+// defendShot sends the kick of the shot and plays it with the away goalkeeper following the plan found in each turn.
+// It returns true if the ball was saved.
+func defendShot(s *sim.Simulator, kick orders.Batch) bool {
+	batches := []orders.Batch{kick}
+	jumped := false
+	for turn := 0; turn < 30; turn++ {
+		snapshot := s.Snapshot()
+		if holder := snapshot.Ball.Holder; holder != nil && holder.TeamPlace == arena.AwayTeam {
+			return true
+		}
+		keeper := snapshot.Player(arena.AwayTeam, arena.GoalkeeperNumber)
+		plan := PlanJumpForBall(keeper.Element, snapshot.Ball.Element, arena.AwayTeamGoal)
+		list := []orders.Order{orders.NewCatchOrder()}
+		switch {
+		case plan.ShouldJumpNow() && !jumped:
+			list = append(list, plan.Order())
+			jumped = true
+		case plan.Threat && !plan.Jump && plan.RunVelocity.Direction != nil:
+			list = append(list, orders.NewMoveOrder(plan.RunVelocity))
+		}
+		if result := s.Step(append(batches, orders.Batch{Place: arena.AwayTeam, Number: arena.GoalkeeperNumber, Orders: list})); result.Goal != nil {
+			return false
+		}
+		batches = nil
+	}
+	holder := s.Snapshot().Ball.Holder
+	return holder != nil && holder.TeamPlace == arena.AwayTeam
+}
+
+func TestBestShot_EmptyGoal(t *testing.T) {
+	s := newTestAttack(physics.Point{PosX: 16000, PosY: 3000}, sim.Lineup{})
+	holder, opponents := attackers(s)
+
+	shot, ok := BestShot(holder, opponents, arena.AwayTeamGoal)
+	assert.True(t, ok)
+	assert.True(t, shot.Scores)
+	assert.Equal(t, 1.0, shot.Chance)
+	// the fastest shot is preferred
+	assert.Equal(t, units.BallMaxSpeed, shot.Velocity.Speed)
+	assert.Equal(t, orders.KICK, shot.Order().Type)
+
+	result := s.Step([]orders.Batch{{Place: arena.HomeTeam, Number: "9", Orders: []orders.Order{shot.Order()}}})
+	for turn := 0; turn < 20 && result.Goal == nil; turn++ {
+		result = s.Step(nil)
+	}
+	assert.NotNil(t, result.Goal)
+}
+
+func TestBestShot_Goalkeeper(t *testing.T) {
+	s := newTestAttack(physics.Point{PosX: 17500, PosY: units.FieldHeight / 2}, sim.Lineup{"1": {PosX: units.PlayerSize, PosY: units.FieldHeight / 2}})
+	holder, opponents := attackers(s)
+
+	shot, ok := BestShot(holder, opponents, arena.AwayTeamGoal)
+	assert.True(t, ok)
+	center := EvaluateShot(holder, opponents, arena.AwayTeamGoal.Center, units.BallMaxSpeed, arena.AwayTeamGoal)
+	assert.Equal(t, 1.0, center.SaveProbability)
+	assert.Equal(t, 0.0, center.Chance)
+
+	// the ball is kicked away from the goalkeeper, close to a pole
+	assert.True(t, math.Abs(float64(shot.Target.PosY-arena.AwayTeamGoal.Center.PosY)) > float64(units.GoalWidth)/4)
+	assert.True(t, shot.Chance > 0.75)
+	assert.True(t, shot.Chance > center.Chance)
+
+	// the goalkeeper follows the jump planner, but cannot save it
+	kick := orders.Batch{Place: arena.HomeTeam, Number: "9", Orders: []orders.Order{shot.Order()}}
+	assert.False(t, defendShot(s, kick))
+}
+
+func TestBestShot_Defender(t *testing.T) {
+	position := physics.Point{PosX: 16000, PosY: units.FieldHeight / 2}
+	// the defender is in front of the lower part of the goal
+	defender := physics.Point{PosX: 17500, PosY: 4500}
+	s := newTestAttack(position, sim.Lineup{"4": arena.Mirror(arena.AwayTeam).Point(defender)})
+	holder, opponents := attackers(s)
+
+	blocked := EvaluateShot(holder, opponents, physics.Point{PosX: units.FieldWidth, PosY: units.GoalMinY + 100}, units.BallMaxSpeed, arena.AwayTeamGoal)
+	assert.True(t, blocked.Scores)
+	assert.NotNil(t, blocked.Interceptor)
+	assert.Equal(t, arena.PlayerNumber("4"), blocked.Interceptor.Number)
+	assert.Equal(t, 1.0, blocked.InterceptionRisk)
+	assert.Equal(t, 0.0, blocked.Chance)
+
+	shot, ok := BestShot(holder, opponents, arena.AwayTeamGoal)
+	assert.True(t, ok)
+	assert.Nil(t, shot.Interceptor)
+	assert.True(t, shot.Target.PosY > arena.AwayTeamGoal.Center.PosY)
+}
+
+func TestBestShot_TooFar(t *testing.T) {
+	s := newTestAttack(physics.Point{PosX: 2000, PosY: units.FieldHeight / 2}, sim.Lineup{})
+	holder, opponents := attackers(s)
+	_, ok := BestShot(holder, opponents, arena.AwayTeamGoal)
+	assert.False(t, ok)
+}
+
+func TestEvaluateShot_MovingHolder(t *testing.T) {
+	s := newTestAttack(physics.Point{PosX: 16000, PosY: 3000}, sim.Lineup{})
+	holder, opponents := attackers(s)
+	holder.Velocity = physics.NewVelocityTo(holder.Coords, physics.Point{PosX: holder.Coords.PosX, PosY: 0}, units.PlayerMaxSpeed)
+
+	shot := EvaluateShot(holder, opponents, arena.AwayTeamGoal.Center, 300, arena.AwayTeamGoal)
+	// the server adds the holder velocity to the kick
+	ball := holder.Velocity.Copy()
+	ball.Add(shot.Velocity)
+	assert.InDelta(t, 300, ball.Speed, 0.001)
+	assert.InDelta(t, shot.BallVelocity.Direction.GetX(), ball.Direction.GetX(), 0.001)
+	assert.InDelta(t, shot.BallVelocity.Direction.GetY(), ball.Direction.GetY(), 0.001)
+}
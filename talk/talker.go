@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"github.com/gorilla/websocket"
 	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/orders"
+	"github.com/lugobots/arena/units"
 	"github.com/sirupsen/logrus"
 	"net"
 	"net/http"
@@ -19,6 +21,10 @@ type Talker interface {
 	Listen() <-chan []byte
 	ListenInterruption() <-chan *websocket.CloseError
 	Close()
+	// Profile returns the rule profile of the game server (see arena.Profile)
+	Profile() arena.Profile
+	// Field returns the field defined by the rules of the game server profile
+	Field() arena.Field
 }
 
 // channel is meant to make the websocket connection and communication easier.
@@ -33,6 +39,8 @@ type channel struct {
 	writingMitx       sync.Mutex
 	logger            *logrus.Entry
 	connectionOpenned bool
+	profile           arena.Profile
+	profileMutex      sync.RWMutex
 }
 
 //	NewTalker creates a new talker that knows how to talk to the game server
@@ -44,8 +52,19 @@ func NewTalker(logger *logrus.Entry) Talker {
 	}
 }
 
-// Connect tries to open a new web socket connection with the game server
+// Connect tries to open a new web socket connection with the game server. The player specifications are validated
+// before dialing (see arena.PlayerSpecifications.Validate), so a protocol version without rule profile is rejected.
+// The profile of the specifications version is used until the WELCOME message selects the server one.
 func (c *channel) Connect(mainCtx context.Context, url url.URL, playerSpec arena.PlayerSpecifications) (ctx context.Context, err error) {
+	if err := playerSpec.Validate(); err != nil {
+		return nil, err
+	}
+	profile, err := arena.Profiles.ForSpecifications(playerSpec)
+	if err != nil {
+		// specifications without a version are validated against the default rules
+		profile = arena.Profile{Rules: units.DefaultRules()}
+	}
+	c.setProfile(profile)
 	c.playerSpec = playerSpec
 	c.urlConnection = url
 	if err := c.dial(); err != nil {
@@ -80,6 +99,47 @@ func (c *channel) Send(data []byte) error {
 	return c.ws.WriteMessage(websocket.TextMessage, data)
 }
 
+// Profile returns the rule profile of the game server. It is the profile of the player specifications protocol
+// version until the WELCOME message is received, then the one announced by the server (see
+// arena.ProfileRegistry.ForWelcome).
+func (c *channel) Profile() arena.Profile {
+	c.profileMutex.RLock()
+	defer c.profileMutex.RUnlock()
+	return c.profile
+}
+
+// Field returns the field defined by the rules of the game server profile
+func (c *channel) Field() arena.Field {
+	return arena.NewFieldFromRules(c.Profile().Rules)
+}
+
+func (c *channel) setProfile(profile arena.Profile) {
+	c.profileMutex.Lock()
+	defer c.profileMutex.Unlock()
+	c.profile = profile
+}
+
+// selectProfile updates the profile when the message is the WELCOME one. Invalid profiles are logged and ignored, so
+// the player keeps the profile of its specifications.
+func (c *channel) selectProfile(message []byte) {
+	var kind struct {
+		Type arena.MsgType `json:"type"`
+	}
+	if err := json.Unmarshal(message, &kind); err != nil || kind.Type != orders.WELCOME {
+		return
+	}
+	var msg arena.GameMessage
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return
+	}
+	profile, err := arena.Profiles.ForWelcome(msg, c.playerSpec.ProtocolVersion)
+	if err != nil {
+		c.logger.Warnf("the welcome message has no valid rule profile: %s", err)
+		return
+	}
+	c.setProfile(profile)
+}
+
 func (c *channel) Close() {
 	c.connectionOpenned = false
 	c.ws.WriteMessage(websocket.CloseNormalClosure, []byte("bye"))
@@ -124,6 +184,7 @@ func (c *channel) keepListenning() {
 			c.connectionCloser() //unexpected
 			return
 		} else {
+			c.selectProfile(message)
 			c.ReaderChan <- message
 		}
 	}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"github.com/gorilla/websocket"
 	"github.com/lugobots/arena"
+	"github.com/lugobots/arena/units"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"net/http"
@@ -18,6 +20,10 @@ var upgrader = websocket.Upgrader{}
 
 var serverTestConnections = map[string]*websocket.Conn{}
 
+// testSpecs have a valid number and initial coords, since the talker does not dial with invalid ones. The protocol
+// version is not required.
+var testSpecs = arena.PlayerSpecifications{Number: "4", InitialCoords: arena.FieldCenter}
+
 func echo(connectionName string) (hand http.HandlerFunc) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		c, err := upgrader.Upgrade(w, r, nil)
@@ -56,15 +62,15 @@ func TestTalker_Connection(t *testing.T) {
 
 	myTalker := NewTalker(logger.WithField("test", "a"))
 	mainCtx := context.Background()
-	_, err := myTalker.Connect(mainCtx, *wsUrl, arena.PlayerSpecifications{})
+	_, err := myTalker.Connect(mainCtx, *wsUrl, testSpecs)
 	assert.Nil(t, err)
 	myTalker.Send([]byte(msgTeste))
 
 	ctxWaitALittle, ack := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer ack()
 	select {
 	case newMsg := <-myTalker.Listen():
 		msgReceived = string(newMsg)
-		ack()
 	case err := <-myTalker.ListenInterruption():
 		assert.Fail(t, err.Text)
 	case <-ctxWaitALittle.Done():
@@ -89,7 +95,7 @@ func TestTalker_ClosingConnection(t *testing.T) {
 	myTalker := NewTalker(logger.WithField("test", "a"))
 	mainCtx := context.Background()
 
-	connectionCtx, err := myTalker.Connect(mainCtx, *wsUrl, arena.PlayerSpecifications{})
+	connectionCtx, err := myTalker.Connect(mainCtx, *wsUrl, testSpecs)
 	assert.Nil(t, err)
 	myTalker.Close()
 
@@ -119,7 +125,7 @@ func TestTalker_UnnexpectedConnectionClosed(t *testing.T) {
 	myTalker := NewTalker(logger.WithField("test", "a"))
 
 	mainCtx := context.Background()
-	connectionCtx, err := myTalker.Connect(mainCtx, *wsUrl, arena.PlayerSpecifications{})
+	connectionCtx, err := myTalker.Connect(mainCtx, *wsUrl, testSpecs)
 	assert.Nil(t, err)
 	go func() {
 		serverTestConnections[connectionName].Close()
@@ -154,9 +160,10 @@ func TestTalker_StopsIfMainCtxStop(t *testing.T) {
 	myTalker := NewTalker(logger.WithField("test", "a"))
 
 	//ctx := context.WithValue(context.Background(), "main", "yes")
-	mainCtx, _ := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	mainCtx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
 
-	connectionCtx, err := myTalker.Connect(mainCtx, *wsUrl, arena.PlayerSpecifications{})
+	connectionCtx, err := myTalker.Connect(mainCtx, *wsUrl, testSpecs)
 	assert.Nil(t, err)
 
 	select {
@@ -168,3 +175,40 @@ func TestTalker_StopsIfMainCtxStop(t *testing.T) {
 		assert.Equal(t, context.DeadlineExceeded, connectionCtx.Err(), "should had been cloased by the main context")
 	}
 }
+
+func TestTalker_ConnectInvalidSpecs(t *testing.T) {
+	myTalker := NewTalker(logrus.New().WithField("test", "invalid"))
+	_, err := myTalker.Connect(context.Background(), url.URL{}, arena.PlayerSpecifications{Number: "12", ProtocolVersion: "1.0"})
+	assert.Equal(t, arena.ErrPlayerNumberOutOfRange, errors.Cause(err))
+}
+
+func TestTalker_ConnectUnknownProtocolVersion(t *testing.T) {
+	myTalker := NewTalker(logrus.New().WithField("test", "unknown version"))
+	_, err := myTalker.Connect(context.Background(), url.URL{}, arena.PlayerSpecifications{Number: "4", ProtocolVersion: "9.0"})
+	assert.Equal(t, arena.ErrUnknownProtocolVersion, errors.Cause(err))
+}
+
+func TestTalker_ProfileFromWelcome(t *testing.T) {
+	s := httptest.NewServer(echo("profile-test"))
+	defer s.Close()
+	wsUrl, _ := url.Parse("ws" + strings.TrimPrefix(s.URL, "http"))
+
+	myTalker := NewTalker(logrus.New().WithField("test", "profile"))
+	_, err := myTalker.Connect(context.Background(), *wsUrl, testSpecs)
+	assert.Nil(t, err)
+	defer myTalker.Close()
+	assert.Equal(t, units.DefaultRules(), myTalker.Profile().Rules)
+
+	// the echo server sends the welcome message back as if it was the game server
+	welcome := `{"type": "welcome", "data": {"protocol_version": "1.0", "rules": {"field_width": 30000}}}`
+	assert.Nil(t, myTalker.Send([]byte(welcome)))
+	select {
+	case <-myTalker.Listen():
+	case <-time.After(time.Second):
+		assert.Fail(t, "the welcome message was not received")
+	}
+	assert.Equal(t, "lugo-1", myTalker.Profile().Name)
+	assert.Equal(t, 30000, myTalker.Profile().Rules.FieldWidth)
+	assert.Equal(t, 30000, myTalker.Field().Width)
+	assert.Equal(t, 15000, myTalker.Field().Center.PosX)
+}
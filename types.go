@@ -42,3 +42,13 @@ type Goal struct {
 	// BottomPole is the coordinates of the pole  with a lower Y coordinate
 	BottomPole physics.Point
 }
+
+// GameMessage is the message sent by the game server to the players and to the web clients
+type GameMessage struct {
+	// Type identifies the message type (e.g. announcement, score, welcome)
+	Type MsgType `json:"type"`
+	// Snapshot is the game state when the message was sent
+	Snapshot Snapshot `json:"info"`
+	// Data has extra values that depend on the message type
+	Data map[string]interface{} `json:"data,omitempty"`
+}
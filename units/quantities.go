@@ -0,0 +1,156 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DefaultPitchLength is the length in meters of the real pitch that is matched to the field width
+const DefaultPitchLength = 105.0
+
+// DefaultTurnDuration is the duration of each turn used when no other duration is specified
+const DefaultTurnDuration = 50 * time.Millisecond
+
+// Distance is a length in field units (the unit of the points coordinates)
+type Distance float64
+
+// Speed is a speed in field units per turn
+type Speed float64
+
+// Turns is a duration in turns
+type Turns float64
+
+// DistanceFromBaseUnits converts a number of base units (see BaseUnit) to a distance
+func DistanceFromBaseUnits(baseUnits float64) Distance {
+	return Distance(baseUnits * BaseUnit)
+}
+
+// BaseUnits returns the distance in base units (see BaseUnit)
+func (d Distance) BaseUnits() float64 {
+	return float64(d) / BaseUnit
+}
+
+// Int returns the distance rounded to be used as a coordinate or a size
+func (d Distance) Int() int {
+	return int(math.Round(float64(d)))
+}
+
+// TurnsAt returns how long it takes to travel the distance at the speed. It is infinite when the speed is not positive.
+func (d Distance) TurnsAt(speed Speed) Turns {
+	if speed <= 0 {
+		return Turns(math.Inf(1))
+	}
+	return Turns(float64(d) / float64(speed))
+}
+
+// String formats the distance in field units
+func (d Distance) String() string {
+	return fmt.Sprintf("%.0f units", float64(d))
+}
+
+// Over returns the distance travelled at the speed during the turns
+func (s Speed) Over(turns Turns) Distance {
+	return Distance(float64(s) * float64(turns))
+}
+
+// String formats the speed in field units per turn
+func (s Speed) String() string {
+	return fmt.Sprintf("%.1f units/turn", float64(s))
+}
+
+// String formats the number of turns
+func (t Turns) String() string {
+	return fmt.Sprintf("%.1f turns", float64(t))
+}
+
+// Scale converts the game quantities to real world quantities. The field is matched to a real pitch, so a field
+// width is the pitch length, and each turn has a fixed duration.
+type Scale struct {
+	// MetersPerUnit is the length in meters of a field unit
+	MetersPerUnit float64
+	// TurnDuration is the time between two turns
+	TurnDuration time.Duration
+	// CellSize is the side of the field cells, in field units (e.g. the cells of a control map or a grid)
+	CellSize int
+}
+
+// NewScale creates a scale that matches the field width defined by the rules to a pitch of length `pitchLength`
+// (in meters), and where each turn lasts `turnDuration`. The cells have the size of a player.
+func NewScale(rules Rules, pitchLength float64, turnDuration time.Duration) Scale {
+	return Scale{
+		MetersPerUnit: pitchLength / float64(rules.FieldWidth),
+		TurnDuration:  turnDuration,
+		CellSize:      rules.PlayerSize,
+	}
+}
+
+// DefaultScale creates a scale for the default rules, the default pitch length and the default turn duration
+func DefaultScale() Scale {
+	return NewScale(DefaultRules(), DefaultPitchLength, DefaultTurnDuration)
+}
+
+// Meters converts the distance to meters
+func (s Scale) Meters(d Distance) float64 {
+	return float64(d) * s.MetersPerUnit
+}
+
+// DistanceFromMeters converts a length in meters to a distance
+func (s Scale) DistanceFromMeters(meters float64) Distance {
+	return Distance(meters / s.MetersPerUnit)
+}
+
+// Cells converts the distance to a number of cells
+func (s Scale) Cells(d Distance) float64 {
+	return float64(d) / float64(s.CellSize)
+}
+
+// DistanceFromCells converts a number of cells to a distance
+func (s Scale) DistanceFromCells(cells float64) Distance {
+	return Distance(cells * float64(s.CellSize))
+}
+
+// Seconds converts the turns to seconds
+func (s Scale) Seconds(t Turns) float64 {
+	return float64(t) * s.TurnDuration.Seconds()
+}
+
+// Duration converts the turns to a duration
+func (s Scale) Duration(t Turns) time.Duration {
+	return time.Duration(float64(t) * float64(s.TurnDuration))
+}
+
+// TurnsIn returns the number of turns played during the duration
+func (s Scale) TurnsIn(d time.Duration) Turns {
+	return Turns(float64(d) / float64(s.TurnDuration))
+}
+
+// MetersPerSecond converts the speed to meters per second
+func (s Scale) MetersPerSecond(speed Speed) float64 {
+	return s.Meters(Distance(speed)) / s.TurnDuration.Seconds()
+}
+
+// KilometersPerHour converts the speed to kilometers per hour
+func (s Scale) KilometersPerHour(speed Speed) float64 {
+	return s.MetersPerSecond(speed) * 3.6
+}
+
+// SpeedFromMetersPerSecond converts a speed in meters per second to field units per turn
+func (s Scale) SpeedFromMetersPerSecond(metersPerSecond float64) Speed {
+	return Speed(float64(s.DistanceFromMeters(metersPerSecond)) * s.TurnDuration.Seconds())
+}
+
+// FormatDistance formats the distance in meters to be shown in logs and reports
+func (s Scale) FormatDistance(d Distance) string {
+	return fmt.Sprintf("%.1f m", s.Meters(d))
+}
+
+// FormatSpeed formats the speed in meters per second to be shown in logs and reports
+func (s Scale) FormatSpeed(speed Speed) string {
+	return fmt.Sprintf("%.1f m/s", s.MetersPerSecond(speed))
+}
+
+// FormatTurns formats the turns as a duration to be shown in logs and reports
+func (s Scale) FormatTurns(t Turns) string {
+	return s.Duration(t).String()
+}
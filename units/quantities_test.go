@@ -0,0 +1,52 @@
+package units
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, Distance(1400), DistanceFromBaseUnits(14))
+	assert.Equal(t, 200.0, Distance(FieldWidth).BaseUnits())
+	assert.Equal(t, 1235, Distance(1234.6).Int())
+	assert.Equal(t, Turns(14), Distance(GoalZoneRange).TurnsAt(PlayerMaxSpeed))
+	assert.True(t, math.IsInf(float64(Distance(10).TurnsAt(0)), 1))
+	assert.Equal(t, Distance(300), Speed(PlayerMaxSpeed).Over(GoalKeeperJumpDuration))
+	assert.Equal(t, "1400 units", Distance(1400).String())
+	assert.Equal(t, "100.0 units/turn", Speed(100).String())
+	assert.Equal(t, "3.0 turns", Turns(3).String())
+}
+
+func TestScale(t *testing.T) {
+	scale := DefaultScale()
+	assert.InDelta(t, 105.0, scale.Meters(FieldWidth), 1e-9)
+	assert.InDelta(t, 52.5, scale.Meters(FieldHeight), 1e-9)
+	assert.InDelta(t, FieldWidth, float64(scale.DistanceFromMeters(105)), 1e-9)
+
+	assert.Equal(t, 50.0, scale.Cells(FieldWidth))
+	assert.Equal(t, Distance(1200), scale.DistanceFromCells(3))
+
+	assert.Equal(t, 0.75, scale.Seconds(BallTimeInGoalZone))
+	assert.Equal(t, 150*time.Millisecond, scale.Duration(GoalKeeperJumpDuration))
+	assert.Equal(t, Turns(1200), scale.TurnsIn(time.Minute))
+
+	// a player runs 0.525 m each 50ms
+	assert.InDelta(t, 10.5, scale.MetersPerSecond(PlayerMaxSpeed), 1e-9)
+	assert.InDelta(t, 37.8, scale.KilometersPerHour(PlayerMaxSpeed), 1e-9)
+	assert.InDelta(t, PlayerMaxSpeed, float64(scale.SpeedFromMetersPerSecond(10.5)), 1e-9)
+
+	assert.Equal(t, "105.0 m", scale.FormatDistance(FieldWidth))
+	assert.Equal(t, "42.0 m/s", scale.FormatSpeed(BallMaxSpeed))
+	assert.Equal(t, "750ms", scale.FormatTurns(BallTimeInGoalZone))
+}
+
+func TestNewScale(t *testing.T) {
+	rules := DefaultRules()
+	rules.FieldWidth = 30000
+	scale := NewScale(rules, 120, 100*time.Millisecond)
+	assert.InDelta(t, 0.004, scale.MetersPerUnit, 1e-12)
+	assert.InDelta(t, 4.0, scale.MetersPerSecond(PlayerMaxSpeed), 1e-9)
+	assert.Equal(t, PlayerSize, scale.CellSize)
+}
@@ -0,0 +1,161 @@
+package units
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Rules holds the values that define the game physics and the field geometry. The constants in this package are the
+// default rules, while a Rules value allows playing with other values (e.g. a bigger field or a faster ball) that may
+// be used by a server variant or a future version.
+type Rules struct {
+	// BaseUnit is the scale of the integer units, see the BaseUnit constant
+	BaseUnit int `json:"base_unit" yaml:"base_unit"`
+	// PlayerSize is the size of each player
+	PlayerSize int `json:"player_size" yaml:"player_size"`
+	// PlayerMaxSpeed is the max speed that a player may move by frame
+	PlayerMaxSpeed float64 `json:"player_max_speed" yaml:"player_max_speed"`
+	// FieldWidth is the width of the field (horizontal view)
+	FieldWidth int `json:"field_width" yaml:"field_width"`
+	// FieldHeight is the height of the field (horizontal view)
+	FieldHeight int `json:"field_height" yaml:"field_height"`
+	// FieldNeutralCenter is the radius of the neutral circle on the center of the field
+	FieldNeutralCenter int `json:"field_neutral_center" yaml:"field_neutral_center"`
+	// BallSize is the size of the ball
+	BallSize int `json:"ball_size" yaml:"ball_size"`
+	// BallDeceleration is the deceleration rate of the ball speed by frame
+	BallDeceleration float64 `json:"ball_deceleration" yaml:"ball_deceleration"`
+	// BallMaxSpeed is the max speed of the ball by frame
+	BallMaxSpeed float64 `json:"ball_max_speed" yaml:"ball_max_speed"`
+	// BallMinSpeed is the speed below which the ball is considered stopped
+	BallMinSpeed float64 `json:"ball_min_speed" yaml:"ball_min_speed"`
+	// BallTimeInGoalZone is the max number of turns that the ball may be in a goal zone before being auto kicked
+	BallTimeInGoalZone int `json:"ball_time_in_goal_zone" yaml:"ball_time_in_goal_zone"`
+	// GoalWidth is the goal width
+	GoalWidth int `json:"goal_width" yaml:"goal_width"`
+	// GoalZoneRange is the minimal distance that a player can stay from the opponent goal
+	GoalZoneRange int `json:"goal_zone_range" yaml:"goal_zone_range"`
+	// GoalKeeperJumpDuration is the number of turns that the jump takes
+	GoalKeeperJumpDuration int `json:"goal_keeper_jump_duration" yaml:"goal_keeper_jump_duration"`
+	// GoalKeeperJumpSpeed is the max speed of the goalkeeper during the jump
+	GoalKeeperJumpSpeed float64 `json:"goal_keeper_jump_speed" yaml:"goal_keeper_jump_speed"`
+}
+
+// DefaultRules returns the rules defined by the constants of this package
+func DefaultRules() Rules {
+	return Rules{
+		BaseUnit:               BaseUnit,
+		PlayerSize:             PlayerSize,
+		PlayerMaxSpeed:         PlayerMaxSpeed,
+		FieldWidth:             FieldWidth,
+		FieldHeight:            FieldHeight,
+		FieldNeutralCenter:     FieldNeutralCenter,
+		BallSize:               BallSize,
+		BallDeceleration:       BallDeceleration,
+		BallMaxSpeed:           BallMaxSpeed,
+		BallMinSpeed:           BallMinSpeed,
+		BallTimeInGoalZone:     BallTimeInGoalZone,
+		GoalWidth:              GoalWidth,
+		GoalZoneRange:          GoalZoneRange,
+		GoalKeeperJumpDuration: GoalKeeperJumpDuration,
+		GoalKeeperJumpSpeed:    GoalKeeperJumpSpeed,
+	}
+}
+
+// GoalMinY is the coordinate Y of the lower pole of the goals
+func (r Rules) GoalMinY() int {
+	return (r.FieldHeight - r.GoalWidth) / 2
+}
+
+// GoalMaxY is the coordinate Y of the upper pole of the goals
+func (r Rules) GoalMaxY() int {
+	return r.GoalMinY() + r.GoalWidth
+}
+
+// Validate checks if the rules describe a playable game
+func (r Rules) Validate() error {
+	positive := []struct {
+		name  string
+		value float64
+	}{
+		{"base_unit", float64(r.BaseUnit)},
+		{"player_size", float64(r.PlayerSize)},
+		{"player_max_speed", r.PlayerMaxSpeed},
+		{"field_width", float64(r.FieldWidth)},
+		{"field_height", float64(r.FieldHeight)},
+		{"ball_size", float64(r.BallSize)},
+		{"ball_deceleration", r.BallDeceleration},
+		{"ball_max_speed", r.BallMaxSpeed},
+		{"goal_width", float64(r.GoalWidth)},
+		{"goal_keeper_jump_duration", float64(r.GoalKeeperJumpDuration)},
+		{"goal_keeper_jump_speed", r.GoalKeeperJumpSpeed},
+	}
+	for _, field := range positive {
+		if field.value <= 0 {
+			return fmt.Errorf("invalid rules: %s must be greater than zero", field.name)
+		}
+	}
+	switch {
+	case r.FieldNeutralCenter < 0, r.BallMinSpeed < 0, r.BallTimeInGoalZone < 0, r.GoalZoneRange < 0:
+		return fmt.Errorf("invalid rules: negative values are not allowed")
+	case r.GoalWidth > r.FieldHeight:
+		return fmt.Errorf("invalid rules: the goal is wider than the field height")
+	case r.PlayerSize > r.FieldHeight || r.PlayerSize > r.FieldWidth:
+		return fmt.Errorf("invalid rules: the players do not fit in the field")
+	case 2*r.GoalZoneRange >= r.FieldWidth:
+		return fmt.Errorf("invalid rules: the goal zones overlap")
+	}
+	return nil
+}
+
+// ParseRulesJSON reads rules from JSON. The values missing in the data are taken from the default rules, and unknown
+// keys are not accepted, as in ParseRulesYAML.
+func ParseRulesJSON(data []byte) (Rules, error) {
+	return ParseRulesJSONOver(DefaultRules(), data)
+}
+
+// ParseRulesJSONOver reads rules from JSON as ParseRulesJSON does, but the values missing in the data are taken from
+// the `base` rules (e.g. a profile whose values are partially replaced)
+func ParseRulesJSONOver(base Rules, data []byte) (Rules, error) {
+	rules := base
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rules); err != nil {
+		return Rules{}, fmt.Errorf("invalid rules: %s", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return Rules{}, err
+	}
+	return rules, nil
+}
+
+// ParseRulesYAML reads rules from YAML. The values missing in the data are taken from the default rules.
+func ParseRulesYAML(data []byte) (Rules, error) {
+	rules := DefaultRules()
+	if err := yaml.UnmarshalStrict(data, &rules); err != nil {
+		return Rules{}, fmt.Errorf("invalid rules: %s", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return Rules{}, err
+	}
+	return rules, nil
+}
+
+// LoadRules reads the rules from a file. Files with the extension .yaml or .yml are read as YAML, any other
+// extension is read as JSON.
+func LoadRules(path string) (Rules, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Rules{}, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseRulesYAML(data)
+	}
+	return ParseRulesJSON(data)
+}
@@ -0,0 +1,102 @@
+package units
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultRules(t *testing.T) {
+	rules := DefaultRules()
+	assert.Nil(t, rules.Validate())
+	assert.Equal(t, GoalMinY, rules.GoalMinY())
+	assert.Equal(t, GoalMaxY, rules.GoalMaxY())
+}
+
+func TestRules_Validate(t *testing.T) {
+	rules := DefaultRules()
+	rules.FieldWidth = 0
+	assert.EqualError(t, rules.Validate(), "invalid rules: field_width must be greater than zero")
+
+	rules = DefaultRules()
+	rules.BallDeceleration = 0
+	assert.EqualError(t, rules.Validate(), "invalid rules: ball_deceleration must be greater than zero")
+
+	rules = DefaultRules()
+	rules.BallMinSpeed = -1
+	assert.EqualError(t, rules.Validate(), "invalid rules: negative values are not allowed")
+
+	rules = DefaultRules()
+	rules.GoalWidth = rules.FieldHeight + 1
+	assert.EqualError(t, rules.Validate(), "invalid rules: the goal is wider than the field height")
+
+	rules = DefaultRules()
+	rules.GoalZoneRange = rules.FieldWidth / 2
+	assert.EqualError(t, rules.Validate(), "invalid rules: the goal zones overlap")
+}
+
+func TestParseRulesJSON(t *testing.T) {
+	rules, err := ParseRulesJSON([]byte(`{"field_width": 30000, "ball_max_speed": 500}`))
+	assert.Nil(t, err)
+	expected := DefaultRules()
+	expected.FieldWidth = 30000
+	expected.BallMaxSpeed = 500
+	assert.Equal(t, expected, rules)
+
+	_, err = ParseRulesJSON([]byte(`{"field_width": "wide"}`))
+	assert.NotNil(t, err)
+	_, err = ParseRulesJSON([]byte(`{"field_height": -1}`))
+	assert.NotNil(t, err)
+	_, err = ParseRulesJSON([]byte(`{"goal_size": 4000}`))
+	assert.NotNil(t, err, "unknown fields must not be ignored")
+}
+
+func TestParseRulesJSONOver(t *testing.T) {
+	base := DefaultRules()
+	base.FieldWidth = 30000
+	rules, err := ParseRulesJSONOver(base, []byte(`{"ball_max_speed": 500}`))
+	assert.Nil(t, err)
+	expected := base
+	expected.BallMaxSpeed = 500
+	assert.Equal(t, expected, rules)
+
+	_, err = ParseRulesJSONOver(base, []byte(`{"goal_size": 4000}`))
+	assert.NotNil(t, err)
+}
+
+func TestParseRulesYAML(t *testing.T) {
+	rules, err := ParseRulesYAML([]byte("goal_width: 4000\ngoal_keeper_jump_duration: 5\n"))
+	assert.Nil(t, err)
+	expected := DefaultRules()
+	expected.GoalWidth = 4000
+	expected.GoalKeeperJumpDuration = 5
+	assert.Equal(t, expected, rules)
+	assert.Equal(t, 3000, rules.GoalMinY())
+	assert.Equal(t, 7000, rules.GoalMaxY())
+
+	_, err = ParseRulesYAML([]byte("goal_size: 4000\n"))
+	assert.NotNil(t, err, "unknown fields must not be ignored")
+}
+
+func TestLoadRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	yamlFile := filepath.Join(dir, "rules.yml")
+	assert.Nil(t, ioutil.WriteFile(yamlFile, []byte("player_max_speed: 150\n"), 0644))
+	rules, err := LoadRules(yamlFile)
+	assert.Nil(t, err)
+	assert.Equal(t, 150.0, rules.PlayerMaxSpeed)
+
+	jsonFile := filepath.Join(dir, "rules.json")
+	assert.Nil(t, ioutil.WriteFile(jsonFile, []byte(`{"player_size": 300}`), 0644))
+	rules, err = LoadRules(jsonFile)
+	assert.Nil(t, err)
+	assert.Equal(t, 300, rules.PlayerSize)
+
+	_, err = LoadRules(filepath.Join(dir, "missing.json"))
+	assert.NotNil(t, err)
+}